@@ -0,0 +1,258 @@
+package galoisfield
+
+import (
+	"errors"
+)
+
+var (
+	ErrTransformSize = errors.New("transform size is out of range")
+)
+
+// AdditiveFFT evaluates and interpolates polynomials on the additive
+// subspaces of GF(2**k), using the "novel polynomial basis" transform of
+// Lin, Chung and Han (2014).  A transform of size n = 2**logn evaluates at the
+// points {i ^ shift : 0 ≤ i < n}, which form a coset of the GF(2)-subspace
+// spanned by 1, 2, 4, ..., 2**(logn-1).  Both directions take O(n log n) field
+// operations, and converting to and from the ordinary (monomial) basis takes
+// O(n log**2 n).
+//
+// The novel basis is built from the normalized subspace polynomials
+//
+//	Ŵ_j(x) = W_j(x) / W_j(2**j),   W_j(x) = ∏_{a < 2**j} (x - a)
+//
+// with basis element X_i = ∏ Ŵ_j over the bits j set in i.  Each W_j is
+// linearized (only the terms x**(2**t) are non-zero), so Ŵ_j(x + y) =
+// Ŵ_j(x) + Ŵ_j(y); this is what lets the transform split in half at each
+// step, just as the multiplicative FFT does with roots of unity.
+type AdditiveFFT struct {
+	field *GF
+	logn  uint
+
+	// lin[j][t] is the coefficient of x**(2**t) in Ŵ_j(x), for j ≤ logn.
+	lin [][]byte
+
+	// table[j][y] is Ŵ_j(y), for j < logn.
+	table [][]byte
+}
+
+// NewAdditiveFFT returns a transform of size 2**logn over the given field.
+// It panics with ErrTransformSize if the field has fewer than 2**logn
+// elements.  If field is nil, Default is used.
+func NewAdditiveFFT(field *GF, logn uint) *AdditiveFFT {
+	if field == nil {
+		field = Default
+	}
+	if logn > uint(field.k) {
+		panic(ErrTransformSize)
+	}
+	fft := &AdditiveFFT{field: field, logn: logn}
+
+	// W_0(x) = x, and W_{j+1}(x) = W_j(x) * W_j(x + 2**j)
+	//                            = W_j(x)**2 + W_j(2**j) * W_j(x).
+	w := []byte{1}
+	for j := uint(0); j <= logn; j++ {
+		if j < uint(field.k) {
+			s := evaluateLinearized(field, w, byte(1<<j))
+			norm := make([]byte, len(w))
+			for t, c := range w {
+				norm[t] = field.Div(c, s)
+			}
+			fft.lin = append(fft.lin, norm)
+
+			next := make([]byte, len(w)+1)
+			for t, c := range w {
+				next[t+1] ^= field.Mul(c, c)
+				next[t] ^= field.Mul(s, c)
+			}
+			w = next
+		} else {
+			// W_k(x) = x**(2**k) - x vanishes on the whole field,
+			// so it cannot be normalized; it is only used to reduce
+			// polynomials of high degree.
+			fft.lin = append(fft.lin, w)
+		}
+	}
+
+	fft.table = make([][]byte, logn)
+	for j := range fft.table {
+		fft.table[j] = make([]byte, field.Size())
+		for y := range fft.table[j] {
+			fft.table[j][y] = evaluateLinearized(field, fft.lin[j], byte(y))
+		}
+	}
+	return fft
+}
+
+// Field returns the Galois field over which this transform works.
+func (fft *AdditiveFFT) Field() *GF { return fft.field }
+
+// Size returns the number of points in the transform.
+func (fft *AdditiveFFT) Size() int { return 1 << fft.logn }
+
+// Forward replaces data, the coefficients of a polynomial in the novel
+// basis, with its values at the points i ^ shift.  It panics with
+// ErrTransformSize if len(data) != Size().
+func (fft *AdditiveFFT) Forward(data []byte, shift byte) {
+	fft.checkSize(data)
+	field := fft.field
+	n := len(data)
+	for r := fft.logn; r > 0; r-- {
+		half := 1 << (r - 1)
+		table := fft.table[r-1]
+		for b := 0; b < n; b += 2 * half {
+			s := table[byte(b)^shift]
+			lo, hi := data[b:b+half], data[b+half:b+2*half]
+			for i := range lo {
+				lo[i] ^= field.Mul(s, hi[i])
+				hi[i] ^= lo[i]
+			}
+		}
+	}
+}
+
+// Inverse undoes Forward, replacing the values at the points i ^ shift
+// with the novel-basis coefficients of the interpolating polynomial.  It
+// panics with ErrTransformSize if len(data) != Size().
+func (fft *AdditiveFFT) Inverse(data []byte, shift byte) {
+	fft.checkSize(data)
+	field := fft.field
+	n := len(data)
+	for r := uint(1); r <= fft.logn; r++ {
+		half := 1 << (r - 1)
+		table := fft.table[r-1]
+		for b := 0; b < n; b += 2 * half {
+			s := table[byte(b)^shift]
+			lo, hi := data[b:b+half], data[b+half:b+2*half]
+			for i := range lo {
+				hi[i] ^= lo[i]
+				lo[i] ^= field.Mul(s, hi[i])
+			}
+		}
+	}
+}
+
+// Evaluate returns the values of a at the points i ^ shift, for
+// 0 ≤ i < Size().  It panics with ErrIncompatibleFields if a is not drawn
+// from this transform's field.
+func (fft *AdditiveFFT) Evaluate(a Polynomial, shift byte) []byte {
+	if a.field != fft.field {
+		panic(ErrIncompatibleFields)
+	}
+	field := fft.field
+	n := fft.Size()
+	coefficients := a.coefficients
+	if len(coefficients) > n {
+		// Reduce modulo the polynomial which vanishes on the
+		// evaluation points, W(x) - W(shift) where W = W_logn.
+		w := fft.lin[fft.logn]
+		m := make([]byte, n+1)
+		for t, c := range w {
+			m[1<<uint(t)] = c
+		}
+		m[0] = evaluateLinearized(field, w, shift)
+		coefficients = polyRem(field, coefficients, m)
+	}
+	data := expand(n, coefficients)
+	fft.toNovel(data, fft.logn)
+	fft.Forward(data, shift)
+	return data
+}
+
+// Interpolate returns the unique polynomial of degree less than Size() which
+// takes the value values[i] at the point i ^ shift.  It panics with
+// ErrTransformSize if len(values) != Size().
+func (fft *AdditiveFFT) Interpolate(values []byte, shift byte) Polynomial {
+	fft.checkSize(values)
+	data := append([]byte(nil), values...)
+	fft.Inverse(data, shift)
+	fft.toMonomial(data, fft.logn)
+	return wrapPolynomial(fft.field, data)
+}
+
+// Mul returns the product a*b, computed by evaluating both polynomials,
+// multiplying the values pointwise, and interpolating.  It panics with
+// ErrPolyOutOfRange if the product has Size() or more coefficients, or with
+// ErrIncompatibleFields if a or b is not drawn from this transform's field.
+func (fft *AdditiveFFT) Mul(a, b Polynomial) Polynomial {
+	if a.field != fft.field || b.field != fft.field {
+		panic(ErrIncompatibleFields)
+	}
+	if a.IsZero() || b.IsZero() {
+		return Polynomial{fft.field, nil}
+	}
+	if len(a.coefficients)+len(b.coefficients)-1 > fft.Size() {
+		panic(ErrPolyOutOfRange)
+	}
+	va := fft.Evaluate(a, 0)
+	vb := fft.Evaluate(b, 0)
+	for i := range va {
+		va[i] = fft.field.Mul(va[i], vb[i])
+	}
+	return fft.Interpolate(va, 0)
+}
+
+func (fft *AdditiveFFT) checkSize(data []byte) {
+	if len(data) != fft.Size() {
+		panic(ErrTransformSize)
+	}
+}
+
+// toNovel converts the first 2**r entries of data in place from the monomial
+// basis to the novel basis.  Writing f = f_lo + Ŵ_{r-1}*f_hi, where both
+// halves have degree less than 2**(r-1), the low half of the novel basis is
+// the conversion of f_lo and the high half is the conversion of f_hi.
+func (fft *AdditiveFFT) toNovel(data []byte, r uint) {
+	if r == 0 {
+		return
+	}
+	field := fft.field
+	half := 1 << (r - 1)
+	w := fft.lin[r-1]
+	inv := field.Inv(w[r-1])
+	// Divide by the sparse polynomial Ŵ_{r-1}, leaving the quotient
+	// in the high half and the remainder in the low half.
+	for i := 2*half - 1; i >= half; i-- {
+		c := field.Mul(data[i], inv)
+		data[i] = c
+		if c == 0 {
+			continue
+		}
+		for t := uint(0); t+1 < r; t++ {
+			data[i-half+(1<<t)] ^= field.Mul(c, w[t])
+		}
+	}
+	fft.toNovel(data[:half], r-1)
+	fft.toNovel(data[half:2*half], r-1)
+}
+
+// toMonomial undoes toNovel.
+func (fft *AdditiveFFT) toMonomial(data []byte, r uint) {
+	if r == 0 {
+		return
+	}
+	field := fft.field
+	half := 1 << (r - 1)
+	w := fft.lin[r-1]
+	fft.toMonomial(data[:half], r-1)
+	fft.toMonomial(data[half:2*half], r-1)
+	for i := half; i < 2*half; i++ {
+		c := data[i]
+		if c == 0 {
+			continue
+		}
+		for t := uint(0); t+1 < r; t++ {
+			data[i-half+(1<<t)] ^= field.Mul(c, w[t])
+		}
+		data[i] = field.Mul(c, w[r-1])
+	}
+}
+
+// evaluateLinearized returns ∑_t lin[t] * y**(2**t).
+func evaluateLinearized(field *GF, lin []byte, y byte) byte {
+	var sum byte
+	for _, c := range lin {
+		sum ^= field.Mul(c, y)
+		y = field.Mul(y, y)
+	}
+	return sum
+}
@@ -0,0 +1,151 @@
+package galoisfield
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestAdditiveFFT_Evaluate(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		q := int(field.Size())
+		for logn := uint(0); logn <= uint(field.k); logn++ {
+			fft := NewAdditiveFFT(field, logn)
+			n := fft.Size()
+			for trial := 0; trial < 4; trial++ {
+				p := make([]byte, prng.Intn(2*n+1))
+				for i := range p {
+					p[i] = byte(prng.Intn(q))
+				}
+				poly := NewPolynomial(field, p...)
+				shift := byte(prng.Intn(q))
+
+				values := fft.Evaluate(poly, shift)
+				for i, v := range values {
+					x := byte(i) ^ shift
+					if expected := poly.Evaluate(x); v != expected {
+						t.Errorf("%v logn=%d: (%v) at x=%d: expected %d, got %d",
+							field, logn, poly, x, expected, v)
+					}
+				}
+
+				actual := fft.Interpolate(values, shift)
+				expected, _ := Interpolate(field, pointsOf(n, shift), values)
+				if !actual.Equal(expected) {
+					t.Errorf("%v logn=%d: expected Interpolate to give (%v), got (%v)",
+						field, logn, expected, actual)
+				}
+				if len(p) <= n && !actual.Equal(poly) {
+					t.Errorf("%v logn=%d: expected Interpolate to recover (%v), got (%v)",
+						field, logn, poly, actual)
+				}
+			}
+		}
+	}
+}
+
+func TestAdditiveFFT_ForwardInverse(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		fft := NewAdditiveFFT(field, uint(field.k))
+		data := make([]byte, fft.Size())
+		for i := range data {
+			data[i] = byte(prng.Intn(len(data)))
+		}
+		original := append([]byte(nil), data...)
+		shift := byte(prng.Intn(len(data)))
+		fft.Forward(data, shift)
+		fft.Inverse(data, shift)
+		for i := range data {
+			if data[i] != original[i] {
+				t.Errorf("%v: expected Inverse(Forward(%v)), got %v", field, original, data)
+				break
+			}
+		}
+	}
+}
+
+func TestAdditiveFFT_Mul(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		q := int(field.Size())
+		fft := NewAdditiveFFT(field, uint(field.k))
+		for trial := 0; trial < 16; trial++ {
+			la := prng.Intn(q/2 + 1)
+			lb := prng.Intn(q/2 + 1)
+			a := make([]byte, la)
+			b := make([]byte, lb)
+			for i := range a {
+				a[i] = byte(prng.Intn(q))
+			}
+			for i := range b {
+				b[i] = byte(prng.Intn(q))
+			}
+			pa := NewPolynomial(field, a...)
+			pb := NewPolynomial(field, b...)
+			expected := pa.Mul(pb)
+			actual := fft.Mul(pa, pb)
+			if !actual.Equal(expected) {
+				t.Errorf("%v: expected (%v)*(%v) = (%v), got (%v)",
+					field, pa, pb, expected, actual)
+			}
+		}
+	}
+}
+
+func TestAdditiveFFT_panics(t *testing.T) {
+	fft := NewAdditiveFFT(Poly310_g2, 2)
+	type testrow struct {
+		fn       func()
+		expected error
+	}
+	for idx, row := range []testrow{
+		testrow{func() { NewAdditiveFFT(Poly310_g2, 4) }, ErrTransformSize},
+		testrow{func() { fft.Forward(make([]byte, 3), 0) }, ErrTransformSize},
+		testrow{func() { fft.Interpolate(make([]byte, 8), 0) }, ErrTransformSize},
+		testrow{func() { fft.Evaluate(NewPolynomial(nil, 1), 0) }, ErrIncompatibleFields},
+		testrow{func() {
+			p := NewPolynomial(Poly310_g2, 1, 1, 1)
+			fft.Mul(p, p)
+		}, ErrPolyOutOfRange},
+	} {
+		if e := panicValue(row.fn); e != row.expected {
+			t.Errorf("[%d] expected panic(%v), got %v", idx, row.expected, e)
+		}
+	}
+}
+
+func pointsOf(n int, shift byte) []byte {
+	xs := make([]byte, n)
+	for i := range xs {
+		xs[i] = byte(i) ^ shift
+	}
+	return xs
+}
+
+func BenchmarkAdditiveFFT_Evaluate_256(b *testing.B) {
+	fft := NewAdditiveFFT(Default, 8)
+	p := benchmarkPolynomial(255)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = fft.Evaluate(p, 0)
+	}
+}
+
+func BenchmarkAdditiveFFT_Forward_256(b *testing.B) {
+	fft := NewAdditiveFFT(Default, 8)
+	data := benchmarkPolynomial(255).Coefficients()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fft.Forward(data, 0)
+	}
+}
+
+func BenchmarkAdditiveFFT_Mul_256(b *testing.B) {
+	fft := NewAdditiveFFT(Default, 8)
+	p := benchmarkPolynomial(127)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = fft.Mul(p, p)
+	}
+}
@@ -0,0 +1,196 @@
+package galoisfield
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestGF_InvSlice(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		q := int(field.Size())
+		for _, n := range []int{0, 1, 2, 7, 64} {
+			src := make([]byte, n)
+			for i := range src {
+				src[i] = byte(1 + prng.Intn(q-1))
+			}
+			x := randomCoefficients(prng, q, n)
+			expectInv, expectDiv := make([]byte, n), make([]byte, n)
+			for i, v := range src {
+				expectInv[i] = field.Inv(v)
+				expectDiv[i] = field.Div(x[i], v)
+			}
+			dst := make([]byte, n)
+			field.InvSlice(dst, src)
+			if !equalBytes(dst, expectInv) {
+				t.Errorf("%v: InvSlice(%v) = %v, expected %v", field, src, dst, expectInv)
+			}
+			field.DivSlice(dst, x, src)
+			if !equalBytes(dst, expectDiv) {
+				t.Errorf("%v: DivSlice(%v, %v) = %v, expected %v", field, x, src, dst, expectDiv)
+			}
+			// In place.
+			field.InvSlice(src, src)
+			if !equalBytes(src, expectInv) {
+				t.Errorf("%v: in-place InvSlice = %v, expected %v", field, src, expectInv)
+			}
+		}
+	}
+}
+
+func TestGF_InvSliceZeros(t *testing.T) {
+	field := Default
+	src := []byte{3, 0, 7, 0, 0, 1}
+	dst := []byte{9, 9, 9, 9, 9, 9}
+	zeros := field.InvSliceZeros(dst, src)
+	if !equalInts(zeros, []int{1, 3, 4}) {
+		t.Errorf("expected zeros at [1 3 4], got %v", zeros)
+	}
+	for i, v := range src {
+		var expected byte
+		if v != 0 {
+			expected = field.Inv(v)
+		}
+		if dst[i] != expected {
+			t.Errorf("[%d] expected %d, got %d", i, expected, dst[i])
+		}
+	}
+	if zeros := field.InvSliceZeros(dst[:1], src[:1]); zeros != nil {
+		t.Errorf("expected no zeros, got %v", zeros)
+	}
+}
+
+func TestExtensionField_InvSlice(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, row := range []struct {
+		base    *GF
+		modulus Polynomial
+	}{
+		{Poly410_g2, LowWeightIrreducible(Poly410_g2, 2)},
+		{Default, LowWeightIrreducible(Default, 3)},
+		{Default, LowWeightIrreducible(Default, 16)},
+		{Default, RandomIrreducible(Default, 5, prng)},
+	} {
+		ef := Extend(row.base, row.modulus)
+		d := int(row.modulus.Degree())
+		q := int(row.base.Size())
+		random := func() Polynomial {
+			return ef.Element(NewPolynomial(row.base, randomCoefficients(prng, q, d)...))
+		}
+		for _, n := range []int{0, 1, 2, 9, 33} {
+			src, x := make([]Polynomial, n), make([]Polynomial, n)
+			for i := range src {
+				for src[i] = random(); src[i].IsZero(); src[i] = random() {
+				}
+				x[i] = random()
+			}
+			dst := make([]Polynomial, n)
+			ef.InvSlice(dst, src)
+			for i, v := range src {
+				if !dst[i].Equal(ef.Inv(v)) {
+					t.Errorf("%v: InvSlice[%d] = (%v), expected (%v)", ef, i, dst[i], ef.Inv(v))
+				}
+			}
+			ef.DivSlice(dst, x, src)
+			for i, v := range src {
+				if expected := ef.Div(x[i], v); !dst[i].Equal(expected) {
+					t.Errorf("%v: DivSlice[%d] = (%v), expected (%v)", ef, i, dst[i], expected)
+				}
+			}
+
+			// Elements that are not reduced are reduced first.
+			if n > 0 {
+				unreduced := []Polynomial{src[0].Add(ef.Modulus().Mul(x[0]))}
+				ef.InvSlice(unreduced, unreduced)
+				if expected := ef.Inv(src[0]); !unreduced[0].Equal(expected) {
+					t.Errorf("%v: InvSlice of unreduced (%v) = (%v), expected (%v)",
+						ef, src[0], unreduced[0], expected)
+				}
+			}
+
+			// Zero some positions, and invert in place.
+			var expectZeros []int
+			for i := range src {
+				if prng.Intn(4) == 0 {
+					src[i] = ef.Zero()
+					expectZeros = append(expectZeros, i)
+				}
+			}
+			orig := append([]Polynomial(nil), src...)
+			zeros := ef.InvSliceZeros(src, src)
+			if !equalInts(zeros, expectZeros) {
+				t.Errorf("%v: expected zeros at %v, got %v", ef, expectZeros, zeros)
+			}
+			for i, v := range orig {
+				expected := ef.Zero()
+				if !v.IsZero() {
+					expected = ef.Inv(v)
+				}
+				if !src[i].Equal(expected) {
+					t.Errorf("%v: InvSliceZeros[%d] = (%v), expected (%v)", ef, i, src[i], expected)
+				}
+			}
+		}
+	}
+}
+
+func TestInvSlice_panics(t *testing.T) {
+	type testrow struct {
+		fn       func()
+		expected error
+	}
+	ef := Extend(Poly410_g2, LowWeightIrreducible(Poly410_g2, 2))
+	x := ef.Element(NewPolynomial(Poly410_g2, 1, 1))
+	bytes := []byte{1, 0, 2}
+	polys := []Polynomial{x, ef.Zero(), x}
+	for idx, row := range []testrow{
+		testrow{func() { Default.InvSlice(bytes, bytes[:2]) }, ErrLengthMismatch},
+		testrow{func() { Default.InvSliceZeros(bytes, bytes[:2]) }, ErrLengthMismatch},
+		testrow{func() { Default.DivSlice(bytes, bytes, bytes[:2]) }, ErrLengthMismatch},
+		testrow{func() { Default.InvSlice(bytes, bytes) }, ErrDivByZero},
+		testrow{func() { Default.DivSlice(bytes, bytes, bytes) }, ErrDivByZero},
+		testrow{func() { ef.InvSlice(polys, polys[:2]) }, ErrLengthMismatch},
+		testrow{func() { ef.InvSliceZeros(polys, polys[:2]) }, ErrLengthMismatch},
+		testrow{func() { ef.DivSlice(polys, polys[:2], polys) }, ErrLengthMismatch},
+		testrow{func() { ef.InvSlice(polys, polys) }, ErrDivByZero},
+		testrow{func() { ef.DivSlice(polys, polys, polys) }, ErrDivByZero},
+		testrow{func() { ef.InvSlice(polys[:1], []Polynomial{NewPolynomial(nil, 1)}) }, ErrIncompatibleFields},
+		testrow{func() { ef.InvSliceZeros(polys[:1], []Polynomial{NewPolynomial(nil, 1)}) }, ErrIncompatibleFields},
+		testrow{func() { ef.DivSlice(polys[:1], []Polynomial{NewPolynomial(nil, 1)}, polys[:1]) }, ErrIncompatibleFields},
+	} {
+		if e := panicValue(row.fn); e != row.expected {
+			t.Errorf("[%d] expected panic(%v), got %v", idx, row.expected, e)
+		}
+	}
+	// A panic leaves dst unchanged.
+	if !equalBytes(bytes, []byte{1, 0, 2}) {
+		t.Errorf("expected [1 0 2] to be unchanged, got %v", bytes)
+	}
+	if !polys[0].Equal(x) || !polys[1].IsZero() || !polys[2].Equal(x) {
+		t.Errorf("expected %v to be unchanged", polys)
+	}
+}
+
+func BenchmarkExtensionField_InvSlice(b *testing.B) {
+	prng := rand.New(rand.NewSource(42))
+	ef := Extend(Default, LowWeightIrreducible(Default, 16))
+	src := make([]Polynomial, 256)
+	for i := range src {
+		coefficients := randomCoefficients(prng, 256, 16)
+		coefficients[0] = 1
+		src[i] = NewPolynomial(Default, coefficients...)
+	}
+	dst := make([]Polynomial, len(src))
+	b.Run("InvSlice", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ef.InvSlice(dst, src)
+		}
+	})
+	b.Run("Inv", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j, x := range src {
+				dst[j] = ef.Inv(x)
+			}
+		}
+	})
+}
@@ -0,0 +1,156 @@
+package galoisfield
+
+// BerlekampMassey returns the connection polynomial C(x) = 1 + c1*x + ... +
+// cL*x**L of the shortest linear feedback shift register that generates seq,
+// together with its length L, the linear complexity of seq.  That is, for
+// every k >= L,
+//
+//	seq[k] + c1*seq[k-1] + ... + cL*seq[k-L] = 0.
+//
+// Note that the degree of C may be less than L.  If field is nil, Default is
+// used.
+//
+// When seq holds the syndromes S1, S2, ... of a Reed-Solomon or BCH word,
+// C is the error locator.
+func BerlekampMassey(field *GF, seq []byte) (Polynomial, uint) {
+	s := NewLFSRSynthesizer(field)
+	for _, x := range seq {
+		s.Add(x)
+	}
+	return s.Connection(), s.LinearComplexity()
+}
+
+// BerlekampMasseyErasures is the errors-and-erasures variant of
+// BerlekampMassey.  The registers are seeded with the erasure locator, the
+// product of (1 - Xi*x) over the known erasure positions Xi, and the result
+// is the errata locator: the product of the erasure locator with the locator
+// of the remaining errors.  With ρ erasures and ν errors, len(seq) >= ρ + 2ν
+// suffices.  It panics with ErrPolyOutOfRange if the erasure locator has a
+// zero constant term.
+func BerlekampMasseyErasures(field *GF, seq []byte, erasures Polynomial) (Polynomial, uint) {
+	s := NewLFSRSynthesizerErasures(field, erasures)
+	for _, x := range seq {
+		s.Add(x)
+	}
+	return s.Connection(), s.LinearComplexity()
+}
+
+// LFSRSynthesizer is the streaming form of the Berlekamp-Massey algorithm.
+// Elements of the sequence are added one at a time, and after each one the
+// synthesizer holds the shortest LFSR generating the sequence so far.  The
+// sequence of linear complexities is its linear complexity profile.
+//
+// The zero value is not usable; create one with NewLFSRSynthesizer or
+// NewLFSRSynthesizerErasures.
+type LFSRSynthesizer struct {
+	field   *GF
+	seq     []byte
+	profile []uint
+
+	c   []byte // connection polynomial
+	b   []byte // connection polynomial before the last length change
+	l   uint   // linear complexity
+	m   uint   // elements since the last length change
+	d   byte   // discrepancy at the last length change
+	rho uint   // number of erasures
+}
+
+// NewLFSRSynthesizer returns a new synthesizer for sequences over field.  If
+// field is nil, Default is used.
+func NewLFSRSynthesizer(field *GF) *LFSRSynthesizer {
+	if field == nil {
+		field = Default
+	}
+	return &LFSRSynthesizer{field: field, c: []byte{1}, b: []byte{1}, m: 1, d: 1}
+}
+
+// NewLFSRSynthesizerErasures returns a new synthesizer seeded with the given
+// erasure locator, as in BerlekampMasseyErasures.  The locator is scaled to
+// have constant term 1.  It panics with ErrPolyOutOfRange if the locator has
+// a zero constant term, or with ErrIncompatibleFields if it is drawn from a
+// different field.
+func NewLFSRSynthesizerErasures(field *GF, erasures Polynomial) *LFSRSynthesizer {
+	s := NewLFSRSynthesizer(field)
+	if erasures.field != s.field {
+		panic(ErrIncompatibleFields)
+	}
+	if erasures.Coefficient(0) == 0 {
+		panic(ErrPolyOutOfRange)
+	}
+	gamma := erasures.Scale(s.field.Inv(erasures.Coefficient(0))).coefficients
+	s.c = append([]byte(nil), gamma...)
+	s.b = append([]byte(nil), gamma...)
+	s.rho = erasures.Degree()
+	s.l = s.rho
+	return s
+}
+
+// Field returns the Galois field from which the sequence is drawn.
+func (s *LFSRSynthesizer) Field() *GF { return s.field }
+
+// Len returns the number of elements added so far.
+func (s *LFSRSynthesizer) Len() int { return len(s.seq) }
+
+// Add appends x to the sequence and returns the updated linear complexity.
+func (s *LFSRSynthesizer) Add(x byte) uint {
+	field := s.field
+	n := uint(len(s.seq))
+	s.seq = append(s.seq, x)
+	if n < s.rho {
+		// The erasure locator already accounts for these elements.
+		s.profile = append(s.profile, s.l)
+		return s.l
+	}
+
+	d := x
+	for j := 1; j < len(s.c) && uint(j) <= n; j++ {
+		d ^= field.Mul(s.c[j], s.seq[n-uint(j)])
+	}
+	if d == 0 {
+		s.m++
+		s.profile = append(s.profile, s.l)
+		return s.l
+	}
+
+	// c -= (d/b) x**m b, in place unless the register grows, in which
+	// case the old c is kept as the new b.
+	grow := 2*s.l <= n+s.rho
+	next := s.c
+	if size := int(s.m) + len(s.b); grow || size > len(next) {
+		if size < len(next) {
+			size = len(next)
+		}
+		next = expand(size, next)
+	}
+	coef := field.Div(d, s.d)
+	for i, bi := range s.b {
+		next[int(s.m)+i] ^= field.Mul(coef, bi)
+	}
+	if grow {
+		s.b = s.c
+		s.l = n + 1 + s.rho - s.l
+		s.d = d
+		s.m = 1
+	} else {
+		s.m++
+	}
+	s.c = reduce(next)
+	s.profile = append(s.profile, s.l)
+	return s.l
+}
+
+// Connection returns the connection polynomial of the shortest LFSR that
+// generates the sequence so far.
+func (s *LFSRSynthesizer) Connection() Polynomial {
+	return NewPolynomial(s.field, s.c...)
+}
+
+// LinearComplexity returns the length of the shortest LFSR that generates the
+// sequence so far.
+func (s *LFSRSynthesizer) LinearComplexity() uint { return s.l }
+
+// Profile returns the linear complexity profile: the linear complexity after
+// each element added so far.
+func (s *LFSRSynthesizer) Profile() []uint {
+	return append([]uint(nil), s.profile...)
+}
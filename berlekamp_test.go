@@ -0,0 +1,157 @@
+package galoisfield
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestBerlekampMassey(t *testing.T) {
+	type testrow struct {
+		seq      []byte
+		expected Polynomial
+		length   uint
+	}
+	for idx, row := range []testrow{
+		testrow{nil, NewPolynomial(nil, 1), 0},
+		testrow{[]byte{0, 0, 0}, NewPolynomial(nil, 1), 0},
+		testrow{[]byte{0, 0, 5}, NewPolynomial(nil, 1, 0, 0, 5), 3},
+		testrow{[]byte{3, 3, 3, 3}, NewPolynomial(nil, 1, 1), 1},
+		// s[k] = s[k-1] + s[k-2]
+		testrow{[]byte{1, 2, 3, 1, 2, 3, 1}, NewPolynomial(nil, 1, 1, 1), 2},
+	} {
+		c, l := BerlekampMassey(nil, row.seq)
+		if !c.Equal(row.expected) || l != row.length {
+			t.Errorf("[%d] %v: expected (%v), %d, got (%v), %d",
+				idx, row.seq, row.expected, row.length, c, l)
+		}
+	}
+}
+
+func TestBerlekampMassey_random(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		q := int(field.Size())
+		for trial := 0; trial < 32; trial++ {
+			l := uint(1 + prng.Intn(12))
+			connection := NewPolynomial(field, append([]byte{1}, randomCoefficients(prng, q, int(l))...)...)
+			seq := generateLFSR(field, connection, randomCoefficients(prng, q, int(l)), 2*l)
+			c, actual := BerlekampMassey(field, seq)
+			if !generates(field, c, actual, seq) {
+				t.Errorf("%v: (%v), %d does not generate %v", field, c, actual, seq)
+			}
+			if actual > l || actual == l && !c.Equal(connection) {
+				t.Errorf("%v: %v: expected at most (%v), %d, got (%v), %d",
+					field, seq, connection, l, c, actual)
+			}
+		}
+	}
+}
+
+func TestLFSRSynthesizer_Profile(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		seq := randomCoefficients(prng, int(field.Size()), 40)
+		s := NewLFSRSynthesizer(field)
+		for k, x := range seq {
+			l := s.Add(x)
+			if _, expected := BerlekampMassey(field, seq[:k+1]); l != expected {
+				t.Errorf("%v: prefix %d: expected complexity %d, got %d", field, k+1, expected, l)
+			}
+		}
+		profile := s.Profile()
+		if len(profile) != len(seq) || s.Len() != len(seq) {
+			t.Fatalf("%v: expected a profile of length %d, got %d", field, len(seq), len(profile))
+		}
+		for k := 1; k < len(profile); k++ {
+			if profile[k] < profile[k-1] {
+				t.Errorf("%v: profile %v decreases at %d", field, profile, k)
+			}
+		}
+		// A random sequence has complexity close to half its length.
+		if l := s.LinearComplexity(); l < 18 || l > 22 {
+			t.Errorf("%v: expected complexity near 20, got %d", field, l)
+		}
+	}
+}
+
+func TestBerlekampMasseyErasures(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		q := int(field.Size())
+		for trial := 0; trial < 32; trial++ {
+			rho, nu := prng.Intn(q/2), prng.Intn(q/4+1)
+			positions := prng.Perm(q - 1)[:rho+nu]
+			gamma := NewPolynomial(field, 1)
+			lambda := NewPolynomial(field, 1)
+			seq := make([]byte, rho+2*nu)
+			for i, p := range positions {
+				xi := field.Exp(byte(p))
+				factor := NewPolynomial(field, 1, xi)
+				y := byte(1 + prng.Intn(q-1))
+				if i < rho {
+					gamma = gamma.Mul(factor)
+				} else {
+					lambda = lambda.Mul(factor)
+				}
+				// Syndromes S1, S2, ... of an error of value y at xi.
+				for k := range seq {
+					seq[k] ^= field.Mul(y, powByte(field, xi, uint(k+1)))
+				}
+			}
+			c, l := BerlekampMasseyErasures(field, seq, gamma.Scale(3))
+			if expected := gamma.Mul(lambda); !c.Equal(expected) || l != uint(rho+nu) {
+				t.Errorf("%v: %d erasures, %d errors: expected (%v), %d, got (%v), %d",
+					field, rho, nu, expected, rho+nu, c, l)
+			}
+			if rho == 0 {
+				if c, l := BerlekampMassey(field, seq); !c.Equal(lambda) || l != uint(nu) {
+					t.Errorf("%v: %d errors: expected (%v), got (%v), %d", field, nu, lambda, c, l)
+				}
+			}
+		}
+	}
+}
+
+func TestLFSRSynthesizer_panics(t *testing.T) {
+	type testrow struct {
+		fn       func()
+		expected error
+	}
+	for idx, row := range []testrow{
+		testrow{func() { NewLFSRSynthesizerErasures(nil, NewPolynomial(nil, 0, 1)) }, ErrPolyOutOfRange},
+		testrow{func() { NewLFSRSynthesizerErasures(nil, NewPolynomial(Poly210_g2, 1)) }, ErrIncompatibleFields},
+	} {
+		if e := panicValue(row.fn); e != row.expected {
+			t.Errorf("[%d] expected panic(%v), got %v", idx, row.expected, e)
+		}
+	}
+}
+
+// generateLFSR returns the first n elements of the sequence generated by the
+// given connection polynomial from the given initial state.
+func generateLFSR(field *GF, connection Polynomial, state []byte, n uint) []byte {
+	seq := append([]byte(nil), state...)
+	for k := len(seq); k < int(n); k++ {
+		var x byte
+		for j := 1; j < len(state)+1; j++ {
+			x ^= field.Mul(connection.Coefficient(uint(j)), seq[k-j])
+		}
+		seq = append(seq, x)
+	}
+	return seq
+}
+
+// generates returns true iff the LFSR of length l with the given connection
+// polynomial generates seq.
+func generates(field *GF, connection Polynomial, l uint, seq []byte) bool {
+	for k := l; k < uint(len(seq)); k++ {
+		x := seq[k]
+		for j := uint(1); j <= l; j++ {
+			x ^= field.Mul(connection.Coefficient(j), seq[k-j])
+		}
+		if x != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,508 @@
+package galoisfield
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrSyntax = errors.New("invalid binary polynomial syntax")
+)
+
+// BinaryPolynomial implements polynomials over GF(2), packed 64 coefficients
+// to a word.  Bit i of word j is the coefficient of x**(64*j + i), so the
+// polynomial x**3 + x + 1 is NewBinaryPolynomial(0xb).  There is no limit on
+// the degree.
+//
+// This is the natural home of CRC generators, LFSR feedback polynomials and
+// BCH generators.  The field polynomial p passed to New is also a binary
+// polynomial, but New takes it packed into a uint, which for the supported
+// fields is Words()[0].  BinaryPolynomial is much faster than a Polynomial
+// over DefaultGF4 with coefficients 0 and 1, since addition is XOR of whole
+// words.
+//
+// Like Polynomial, BinaryPolynomial is an immutable value type.
+type BinaryPolynomial struct {
+	words []uint64 // reduced: no leading zero words, nil for zero
+}
+
+// NewBinaryPolynomial returns a new binary polynomial with the given packed
+// coefficients, in little-endian word order.  The words are copied.
+func NewBinaryPolynomial(words ...uint64) BinaryPolynomial {
+	return BinaryPolynomial{trimWords(append([]uint64(nil), words...))}
+}
+
+// BinaryMonomials returns the sum of x**d over the given degrees.  Repeated
+// degrees cancel in pairs.  For example, BinaryMonomials(1279, 216, 0) is
+// the trinomial x**1279 + x**216 + 1.
+func BinaryMonomials(degrees ...uint) BinaryPolynomial {
+	var words []uint64
+	for _, d := range degrees {
+		if n := int(d/64) + 1; n > len(words) {
+			words = append(words, make([]uint64, n-len(words))...)
+		}
+		words[d/64] ^= 1 << (d % 64)
+	}
+	return BinaryPolynomial{trimWords(words)}
+}
+
+// ParseBinaryPolynomial parses a binary polynomial written either in hex, as
+// returned by Hex (e.g. "0x11d"), or algebraically, as returned by String
+// (e.g. "x^8 + x^4 + x^3 + x^2 + 1").  It returns ErrSyntax if s is in
+// neither format.
+func ParseBinaryPolynomial(s string) (BinaryPolynomial, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		return parseBinaryHex(s[2:])
+	}
+	if s == "0" {
+		return BinaryPolynomial{}, nil
+	}
+	var degrees []uint
+	for _, term := range strings.Split(s, "+") {
+		term = strings.TrimSpace(term)
+		switch {
+		case term == "1":
+			degrees = append(degrees, 0)
+		case term == "x":
+			degrees = append(degrees, 1)
+		case strings.HasPrefix(term, "x^"):
+			d, err := strconv.ParseUint(term[2:], 10, 0)
+			if err != nil {
+				return BinaryPolynomial{}, ErrSyntax
+			}
+			degrees = append(degrees, uint(d))
+		default:
+			return BinaryPolynomial{}, ErrSyntax
+		}
+	}
+	return BinaryMonomials(degrees...), nil
+}
+
+func parseBinaryHex(digits string) (BinaryPolynomial, error) {
+	if digits == "" {
+		return BinaryPolynomial{}, ErrSyntax
+	}
+	words := make([]uint64, (len(digits)+15)/16)
+	for i := range words {
+		end := len(digits) - 16*i
+		start := end - 16
+		if start < 0 {
+			start = 0
+		}
+		w, err := strconv.ParseUint(digits[start:end], 16, 64)
+		if err != nil {
+			return BinaryPolynomial{}, ErrSyntax
+		}
+		words[i] = w
+	}
+	return BinaryPolynomial{trimWords(words)}, nil
+}
+
+// Binary returns this polynomial as a binary polynomial.  It panics with
+// ErrPolyOutOfRange if any coefficient is neither 0 nor 1.
+func (a Polynomial) Binary() BinaryPolynomial {
+	var degrees []uint
+	for d, c := range a.coefficients {
+		switch c {
+		case 0:
+		case 1:
+			degrees = append(degrees, uint(d))
+		default:
+			panic(ErrPolyOutOfRange)
+		}
+	}
+	return BinaryMonomials(degrees...)
+}
+
+// Polynomial returns this polynomial as a Polynomial over the given field,
+// which contains GF(2) as its subfield {0, 1}.  If field is nil, Default is
+// used.
+func (a BinaryPolynomial) Polynomial(field *GF) Polynomial {
+	if field == nil {
+		field = Default
+	}
+	if a.IsZero() {
+		return Polynomial{field, nil}
+	}
+	coefficients := make([]byte, a.Degree()+1)
+	for i := range coefficients {
+		coefficients[i] = a.Coefficient(uint(i))
+	}
+	return wrapPolynomial(field, coefficients)
+}
+
+// IsZero returns true iff this polynomial has no terms.
+func (a BinaryPolynomial) IsZero() bool { return len(a.words) == 0 }
+
+// Degree returns the degree of this polynomial, with the convention that the
+// polynomial of zero terms has degree 0.
+func (a BinaryPolynomial) Degree() uint {
+	if a.IsZero() {
+		return 0
+	}
+	return wordsDegree(a.words)
+}
+
+// Coefficient returns the coefficient of the i'th term, 0 or 1.
+func (a BinaryPolynomial) Coefficient(i uint) byte {
+	if i/64 >= uint(len(a.words)) {
+		return 0
+	}
+	return byte(a.words[i/64]>>(i%64)) & 1
+}
+
+// Weight returns the number of non-zero terms.
+func (a BinaryPolynomial) Weight() uint {
+	var n int
+	for _, w := range a.words {
+		n += bits.OnesCount64(w)
+	}
+	return uint(n)
+}
+
+// Words returns the packed coefficients, in little-endian word order.  The
+// result is a copy.
+func (a BinaryPolynomial) Words() []uint64 {
+	return append([]uint64(nil), a.words...)
+}
+
+// Add returns the sum of one or more polynomials.
+func (first BinaryPolynomial) Add(rest ...BinaryPolynomial) BinaryPolynomial {
+	n := len(first.words)
+	for _, next := range rest {
+		if len(next.words) > n {
+			n = len(next.words)
+		}
+	}
+	sum := make([]uint64, n)
+	copy(sum, first.words)
+	for _, next := range rest {
+		for i, w := range next.words {
+			sum[i] ^= w
+		}
+	}
+	return BinaryPolynomial{trimWords(sum)}
+}
+
+// Mul returns the product of one or more polynomials.  The words are
+// multiplied by carry-less multiplication, as by the CLMUL instruction.
+func (first BinaryPolynomial) Mul(rest ...BinaryPolynomial) BinaryPolynomial {
+	prod := first.words
+	for _, next := range rest {
+		prod = wordsMul(prod, next.words)
+	}
+	return BinaryPolynomial{prod}
+}
+
+// DivMod returns the quotient and remainder of a divided by b, such that
+// a = q*b + r and deg(r) < deg(b).  It panics with ErrDivByZero if b is the
+// zero polynomial.
+func (a BinaryPolynomial) DivMod(b BinaryPolynomial) (q, r BinaryPolynomial) {
+	if b.IsZero() {
+		panic(ErrDivByZero)
+	}
+	rem := append([]uint64(nil), a.words...)
+	var quo []uint64
+	if !a.IsZero() && a.Degree() >= b.Degree() {
+		quo = make([]uint64, (a.Degree()-b.Degree())/64+1)
+	}
+	rem = wordsDivMod(quo, rem, b.words)
+	return BinaryPolynomial{trimWords(quo)}, BinaryPolynomial{rem}
+}
+
+// Mod returns the remainder of a divided by b.  It panics with ErrDivByZero
+// if b is the zero polynomial.
+func (a BinaryPolynomial) Mod(b BinaryPolynomial) BinaryPolynomial {
+	if b.IsZero() {
+		panic(ErrDivByZero)
+	}
+	return BinaryPolynomial{wordsDivMod(nil, append([]uint64(nil), a.words...), b.words)}
+}
+
+// GCD returns the greatest common divisor of a and b.  The GCD of two zero
+// polynomials is the zero polynomial.
+func (a BinaryPolynomial) GCD(b BinaryPolynomial) BinaryPolynomial {
+	for !b.IsZero() {
+		a, b = b, a.Mod(b)
+	}
+	return a
+}
+
+// PowMod returns a**e mod m.  It panics with ErrDivByZero if m is zero, or
+// with ErrNegativeExponent if e is negative.
+func (a BinaryPolynomial) PowMod(e *big.Int, m BinaryPolynomial) BinaryPolynomial {
+	if m.IsZero() {
+		panic(ErrDivByZero)
+	}
+	if e.Sign() < 0 {
+		panic(ErrNegativeExponent)
+	}
+	base := a.Mod(m).words
+	result := wordsDivMod(nil, []uint64{1}, m.words)
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		result = wordsDivMod(nil, wordsSqr(result), m.words)
+		if e.Bit(i) != 0 {
+			result = wordsDivMod(nil, wordsMul(result, base), m.words)
+		}
+	}
+	return BinaryPolynomial{result}
+}
+
+// IsIrreducible returns true iff this polynomial has positive degree and
+// cannot be written as the product of two polynomials of lower degree.  It
+// uses Rabin's test, as Polynomial.IsIrreducible does.
+func (a BinaryPolynomial) IsIrreducible() bool {
+	n := a.Degree()
+	if n == 0 {
+		return false
+	}
+	x := BinaryPolynomial{[]uint64{2}}
+	for _, p := range primeFactors(uint64(n)) {
+		h := BinaryPolynomial{binaryXPow2Mod(n/uint(p), a.words)}
+		if a.GCD(h.Add(x)).Degree() != 0 {
+			return false
+		}
+	}
+	h := BinaryPolynomial{binaryXPow2Mod(n, a.words)}
+	return h.Equal(x.Mod(a))
+}
+
+// IsPrimitive returns true iff this polynomial is irreducible and x has
+// multiplicative order 2**n - 1 modulo it, where n is its degree.  These are
+// the feedback polynomials of maximal-length LFSRs.
+//
+// As for Polynomial.IsPrimitive, the prime factors of 2**n - 1 are computed
+// (and cached) on demand, so the first test at a large degree may be slow.
+func (a BinaryPolynomial) IsPrimitive() bool {
+	if a.Coefficient(0) == 0 || !a.IsIrreducible() {
+		return false
+	}
+	n := a.Degree()
+	order := new(big.Int).Lsh(big.NewInt(1), n)
+	order.Sub(order, big.NewInt(1))
+	x := BinaryPolynomial{[]uint64{2}}
+	one := BinaryPolynomial{[]uint64{1}}
+	var e big.Int
+	for _, p := range mersenneFactors(n) {
+		e.Quo(order, p)
+		if x.PowMod(&e, a).Equal(one) {
+			return false
+		}
+	}
+	return true
+}
+
+// Compare defines a total order for binary polynomials: -1 if a < b, 0 if
+// a == b, +1 if a > b.  It agrees with the order of the packed integers.
+func (a BinaryPolynomial) Compare(b BinaryPolynomial) int {
+	if len(a.words) != len(b.words) {
+		if len(a.words) < len(b.words) {
+			return -1
+		}
+		return 1
+	}
+	for i := len(a.words) - 1; i >= 0; i-- {
+		if a.words[i] != b.words[i] {
+			if a.words[i] < b.words[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// Equal returns true iff a == b.
+func (a BinaryPolynomial) Equal(b BinaryPolynomial) bool {
+	return a.Compare(b) == 0
+}
+
+// Less returns true iff a < b.
+func (a BinaryPolynomial) Less(b BinaryPolynomial) bool {
+	return a.Compare(b) < 0
+}
+
+// Hex returns the packed coefficients as a hexadecimal integer, such as
+// "0x11d" for x**8 + x**4 + x**3 + x**2 + 1.
+func (a BinaryPolynomial) Hex() string {
+	if a.IsZero() {
+		return "0x0"
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "0x%x", a.words[len(a.words)-1])
+	for i := len(a.words) - 2; i >= 0; i-- {
+		fmt.Fprintf(&buf, "%016x", a.words[i])
+	}
+	return buf.String()
+}
+
+// GoString returns a Go-syntax representation of this polynomial.
+func (a BinaryPolynomial) GoString() string {
+	var buf bytes.Buffer
+	buf.WriteString("NewBinaryPolynomial(")
+	for i, w := range a.words {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		fmt.Fprintf(&buf, "0x%x", w)
+	}
+	buf.WriteByte(')')
+	return buf.String()
+}
+
+// String returns a human-readable algebraic representation of this
+// polynomial, in the same format as Polynomial.String.
+func (a BinaryPolynomial) String() string {
+	if a.IsZero() {
+		return "0"
+	}
+	var buf bytes.Buffer
+	for i := len(a.words) - 1; i >= 0; i-- {
+		for w := a.words[i]; w != 0; {
+			b := uint(bits.Len64(w)) - 1
+			w &^= 1 << b
+			d := 64*uint(i) + b
+			if buf.Len() > 0 {
+				buf.WriteString(" + ")
+			}
+			switch d {
+			case 0:
+				buf.WriteByte('1')
+			case 1:
+				buf.WriteByte('x')
+			default:
+				fmt.Fprintf(&buf, "x^%d", d)
+			}
+		}
+	}
+	return buf.String()
+}
+
+// binaryXPow2Mod returns x**(2**i) mod m.
+func binaryXPow2Mod(i uint, m []uint64) []uint64 {
+	x := wordsDivMod(nil, []uint64{2}, m)
+	for ; i > 0; i-- {
+		x = wordsDivMod(nil, wordsSqr(x), m)
+	}
+	return x
+}
+
+// clmul returns the 128-bit carry-less product of a and b.
+func clmul(a, b uint64) (hi, lo uint64) {
+	for b != 0 {
+		i := uint(bits.TrailingZeros64(b))
+		b &= b - 1
+		lo ^= a << i
+		if i > 0 {
+			hi ^= a >> (64 - i)
+		}
+	}
+	return hi, lo
+}
+
+// wordsMul returns the reduced product of two packed polynomials.
+func wordsMul(a, b []uint64) []uint64 {
+	if len(a) == 0 || len(b) == 0 {
+		return nil
+	}
+	prod := make([]uint64, len(a)+len(b))
+	for i, ai := range a {
+		if ai == 0 {
+			continue
+		}
+		for j, bj := range b {
+			hi, lo := clmul(ai, bj)
+			prod[i+j] ^= lo
+			prod[i+j+1] ^= hi
+		}
+	}
+	return trimWords(prod)
+}
+
+// wordsSqr returns the reduced square of a packed polynomial.  By the
+// Frobenius map, squaring spreads the bits apart, putting bit i at bit 2i.
+func wordsSqr(a []uint64) []uint64 {
+	if len(a) == 0 {
+		return nil
+	}
+	sq := make([]uint64, 2*len(a))
+	for i, w := range a {
+		sq[2*i] = spreadBits(uint32(w))
+		sq[2*i+1] = spreadBits(uint32(w >> 32))
+	}
+	return trimWords(sq)
+}
+
+// spreadBits returns x with a zero bit inserted above each of its bits.
+func spreadBits(x uint32) uint64 {
+	y := uint64(x)
+	y = (y | y<<16) & 0x0000ffff0000ffff
+	y = (y | y<<8) & 0x00ff00ff00ff00ff
+	y = (y | y<<4) & 0x0f0f0f0f0f0f0f0f
+	y = (y | y<<2) & 0x3333333333333333
+	y = (y | y<<1) & 0x5555555555555555
+	return y
+}
+
+// wordsDivMod divides rem by the non-zero divisor in place, and returns the
+// reduced remainder as a prefix of rem.  If quo is not nil, the quotient bits
+// are XORed into it; it must have room for them.
+func wordsDivMod(quo, rem, divisor []uint64) []uint64 {
+	rem = trimWords(rem)
+	n := wordsDegree(divisor)
+	for len(rem) > 0 {
+		d := wordsDegree(rem)
+		if d < n {
+			break
+		}
+		shift := d - n
+		if quo != nil {
+			quo[shift/64] |= 1 << (shift % 64)
+		}
+		xorShifted(rem, divisor, shift)
+		rem = trimWords(rem)
+	}
+	return rem
+}
+
+// xorShifted XORs src, shifted up by s bits, into dst, which must be long
+// enough to hold it.
+func xorShifted(dst, src []uint64, s uint) {
+	words, b := s/64, s%64
+	if b == 0 {
+		for i, w := range src {
+			dst[uint(i)+words] ^= w
+		}
+		return
+	}
+	for i, w := range src {
+		j := uint(i) + words
+		dst[j] ^= w << b
+		if hi := w >> (64 - b); hi != 0 {
+			dst[j+1] ^= hi
+		}
+	}
+}
+
+// wordsDegree returns the degree of a non-zero reduced packed polynomial.
+func wordsDegree(a []uint64) uint {
+	return 64*uint(len(a)-1) + uint(bits.Len64(a[len(a)-1])) - 1
+}
+
+// trimWords removes leading zero words, returning nil for zero.
+func trimWords(a []uint64) []uint64 {
+	n := len(a)
+	for n > 0 && a[n-1] == 0 {
+		n--
+	}
+	if n == 0 {
+		return nil
+	}
+	return a[:n]
+}
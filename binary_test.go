@@ -0,0 +1,185 @@
+package galoisfield
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func TestBinaryPolynomial_formats(t *testing.T) {
+	type testrow struct {
+		input  BinaryPolynomial
+		str    string
+		hex    string
+		gostr  string
+		degree uint
+	}
+	for idx, row := range []testrow{
+		testrow{NewBinaryPolynomial(), "0", "0x0", "NewBinaryPolynomial()", 0},
+		testrow{NewBinaryPolynomial(0, 0), "0", "0x0", "NewBinaryPolynomial()", 0},
+		testrow{NewBinaryPolynomial(1), "1", "0x1", "NewBinaryPolynomial(0x1)", 0},
+		testrow{NewBinaryPolynomial(0xb), "x^3 + x + 1", "0xb", "NewBinaryPolynomial(0xb)", 3},
+		testrow{NewBinaryPolynomial(0x11d), "x^8 + x^4 + x^3 + x^2 + 1", "0x11d",
+			"NewBinaryPolynomial(0x11d)", 8},
+		testrow{BinaryMonomials(64, 1, 0), "x^64 + x + 1", "0x10000000000000003",
+			"NewBinaryPolynomial(0x3, 0x1)", 64},
+		testrow{BinaryMonomials(3, 3, 130), "x^130", "0x400000000000000000000000000000000",
+			"NewBinaryPolynomial(0x0, 0x0, 0x4)", 130},
+	} {
+		if actual := row.input.String(); actual != row.str {
+			t.Errorf("[%d] String: expected %q, got %q", idx, row.str, actual)
+		}
+		if actual := row.input.Hex(); actual != row.hex {
+			t.Errorf("[%d] Hex: expected %q, got %q", idx, row.hex, actual)
+		}
+		if actual := row.input.GoString(); actual != row.gostr {
+			t.Errorf("[%d] GoString: expected %q, got %q", idx, row.gostr, actual)
+		}
+		if actual := row.input.Degree(); actual != row.degree {
+			t.Errorf("[%d] Degree: expected %d, got %d", idx, row.degree, actual)
+		}
+		for _, s := range []string{row.str, row.hex} {
+			if p, err := ParseBinaryPolynomial(s); err != nil || !p.Equal(row.input) {
+				t.Errorf("[%d] Parse(%q): expected (%v), got (%v), %v", idx, s, row.input, p, err)
+			}
+		}
+	}
+}
+
+func TestParseBinaryPolynomial_errors(t *testing.T) {
+	for _, s := range []string{"", "0x", "0xg", "x^", "x^-1", "y", "x + + 1", "2"} {
+		if p, err := ParseBinaryPolynomial(s); err != ErrSyntax {
+			t.Errorf("Parse(%q): expected ErrSyntax, got (%v), %v", s, p, err)
+		}
+	}
+	if p, err := ParseBinaryPolynomial(" x + x + x^2 "); err != nil || !p.Equal(NewBinaryPolynomial(4)) {
+		t.Errorf("expected repeated terms to cancel, got (%v), %v", p, err)
+	}
+}
+
+func TestBinaryPolynomial_arithmetic(t *testing.T) {
+	// GF(2) is the subfield {0, 1} of every field, so binary arithmetic
+	// must agree with Polynomial arithmetic on 0/1 coefficients.
+	prng := rand.New(rand.NewSource(42))
+	for trial := 0; trial < 64; trial++ {
+		a := randomBinary(prng, prng.Intn(200))
+		b := randomBinary(prng, prng.Intn(200))
+		m := randomBinary(prng, 1+prng.Intn(150))
+		pa, pb, pm := a.Polynomial(nil), b.Polynomial(nil), m.Polynomial(nil)
+		check := func(name string, expected Polynomial, actual BinaryPolynomial) {
+			if !actual.Polynomial(nil).Equal(expected) {
+				t.Errorf("%s of (%v), (%v): expected (%v), got (%v)", name, a, b, expected, actual)
+			}
+		}
+		check("Add", pa.Add(pb), a.Add(b))
+		check("Mul", pa.Mul(pb), a.Mul(b))
+		check("Mul (square)", pa.Mul(pa), a.Mul(a))
+		q, r := a.Mul(b).DivMod(m)
+		pq, pr := pa.Mul(pb).DivMod(pm)
+		check("DivMod quotient", pq, q)
+		check("DivMod remainder", pr, r)
+		check("Mod", pr, a.Mul(b).Mod(m))
+		check("GCD", pa.GCD(pb), a.GCD(b))
+		e := big.NewInt(int64(prng.Intn(1000)))
+		check("PowMod", pa.PowMod(e, pm), a.PowMod(e, m))
+		if !pa.Binary().Equal(a) {
+			t.Errorf("Binary: round trip of (%v) gave (%v)", a, pa.Binary())
+		}
+		if a.Weight() != uint(len(pa.Sparse().Terms())) {
+			t.Errorf("Weight of (%v): expected %d, got %d", a, len(pa.Sparse().Terms()), a.Weight())
+		}
+	}
+}
+
+func TestBinaryPolynomial_IsIrreducible(t *testing.T) {
+	// The numbers of irreducible and primitive binary polynomials of each
+	// degree (OEIS A001037 and A011260).
+	irreducible := []int{0, 2, 1, 2, 3, 6, 9, 18, 30, 56, 99}
+	primitive := []int{0, 1, 1, 2, 2, 6, 6, 18, 16, 48, 60}
+	for n := uint(1); n < uint(len(irreducible)); n++ {
+		var ni, np int
+		for w := uint64(1) << n; w < 2<<n; w++ {
+			p := NewBinaryPolynomial(w)
+			if p.IsIrreducible() {
+				ni++
+				if n > 1 && isReducible(uint(w)) {
+					t.Errorf("(%v) disagrees with isReducible", p)
+				}
+			}
+			if p.IsPrimitive() {
+				np++
+			}
+		}
+		if ni != irreducible[n] || np != primitive[n] {
+			t.Errorf("degree %d: expected %d irreducible and %d primitive, got %d and %d",
+				n, irreducible[n], primitive[n], ni, np)
+		}
+	}
+	// Trinomials and pentanomials from the standards, all primitive.
+	for _, p := range []BinaryPolynomial{
+		BinaryMonomials(64, 4, 3, 1, 0),
+		BinaryMonomials(127, 1, 0),
+		BinaryMonomials(128, 7, 2, 1, 0),
+		BinaryMonomials(1279, 216, 0),
+	} {
+		if !p.IsIrreducible() || !p.IsPrimitive() {
+			t.Errorf("expected (%v) to be primitive", p)
+		}
+		if p.Mul(p).IsIrreducible() || p.Mul(NewBinaryPolynomial(3)).IsIrreducible() {
+			t.Errorf("expected multiples of (%v) to be reducible", p)
+		}
+	}
+}
+
+func TestBinaryPolynomial_Compare(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for trial := 0; trial < 64; trial++ {
+		a, b := randomBinary(prng, prng.Intn(130)), randomBinary(prng, prng.Intn(130))
+		ia, _ := new(big.Int).SetString(a.Hex()[2:], 16)
+		ib, _ := new(big.Int).SetString(b.Hex()[2:], 16)
+		if a.Compare(b) != ia.Cmp(ib) {
+			t.Errorf("Compare(%v, %v): expected %d, got %d", a, b, ia.Cmp(ib), a.Compare(b))
+		}
+	}
+}
+
+func TestBinaryPolynomial_panics(t *testing.T) {
+	type testrow struct {
+		fn       func()
+		expected error
+	}
+	for idx, row := range []testrow{
+		testrow{func() { NewBinaryPolynomial(5).DivMod(NewBinaryPolynomial()) }, ErrDivByZero},
+		testrow{func() { NewBinaryPolynomial(5).Mod(NewBinaryPolynomial()) }, ErrDivByZero},
+		testrow{func() { NewBinaryPolynomial(5).PowMod(big.NewInt(2), NewBinaryPolynomial()) }, ErrDivByZero},
+		testrow{func() { NewBinaryPolynomial(5).PowMod(big.NewInt(-2), NewBinaryPolynomial(7)) }, ErrNegativeExponent},
+		testrow{func() { NewPolynomial(nil, 1, 2).Binary() }, ErrPolyOutOfRange},
+	} {
+		if e := panicValue(row.fn); e != row.expected {
+			t.Errorf("[%d] expected panic(%v), got %v", idx, row.expected, e)
+		}
+	}
+}
+
+// randomBinary returns a random binary polynomial of degree n-1, or zero if
+// n is 0.
+func randomBinary(prng *rand.Rand, n int) BinaryPolynomial {
+	if n == 0 {
+		return BinaryPolynomial{}
+	}
+	words := make([]uint64, (n+63)/64)
+	for i := range words {
+		words[i] = prng.Uint64()
+	}
+	top := uint(n-1) % 64
+	words[len(words)-1] &= 2<<top - 1
+	words[len(words)-1] |= 1 << top
+	return NewBinaryPolynomial(words...)
+}
+
+func BenchmarkBinaryPolynomial_IsIrreducible(b *testing.B) {
+	p := BinaryMonomials(1279, 216, 0)
+	for i := 0; i < b.N; i++ {
+		p.IsIrreducible()
+	}
+}
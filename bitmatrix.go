@@ -0,0 +1,576 @@
+package galoisfield
+
+import (
+	"bytes"
+	"fmt"
+	"math/bits"
+	"strconv"
+)
+
+// m4riBits is the number of rows combined by each table of the Method of
+// Four Russians.  Tables of 2**8 rows balance the cost of building a table
+// against the XORs it saves.  It must divide 64.
+const m4riBits = 8
+
+// BitMatrix implements matrices over GF(2), with each row packed 64 entries
+// to a word: the entry in row i and column j is bit j%64 of word j/64 of the
+// row.  Adding rows is XOR of whole words, so BitMatrix is eight times
+// smaller, and many times faster, than a Matrix over a field of size 256.
+//
+// Bit matrices are the linear maps on GF(2**k) viewed as a vector space over
+// GF(2): see GF.MulBitMatrix and Matrix.BitMatrix.  They also combine CRCs,
+// and drive bit-matrix erasure codes, which encode with XOR alone.
+//
+// Like Matrix, BitMatrix is an immutable value type, with the same panics:
+// operations panic with ErrDimensionMismatch if the shapes of their operands
+// are incompatible.
+type BitMatrix struct {
+	rows, cols int
+	stride     int      // words per row
+	words      []uint64 // row-major; bits beyond cols are zero
+}
+
+// NewBitMatrix returns a new rows×cols bit matrix with the given packed rows,
+// in row-major order: each row takes (cols+63)/64 words, the first holding
+// columns 0 to 63.  The words are copied; missing words are zero.  It panics
+// with ErrDimensionMismatch if there are too many words, or if a word has bits
+// set beyond the last column.
+//
+// For example, NewBitMatrix(2, 2, 0x1, 0x3) is the matrix [10; 11].
+func NewBitMatrix(rows, cols int, words ...uint64) BitMatrix {
+	if rows < 0 || cols < 0 {
+		panic(ErrDimensionMismatch)
+	}
+	m := newBitMatrix(rows, cols)
+	if len(words) > len(m.words) {
+		panic(ErrDimensionMismatch)
+	}
+	copy(m.words, words)
+	for i := 0; i < rows && cols > 0; i++ {
+		if m.row(i)[m.stride-1]&^m.lastMask() != 0 {
+			panic(ErrDimensionMismatch)
+		}
+	}
+	return m
+}
+
+// BitIdentity returns the n×n identity bit matrix.
+func BitIdentity(n int) BitMatrix {
+	m := newBitMatrix(n, n)
+	for i := 0; i < n; i++ {
+		m.set(i, i)
+	}
+	return m
+}
+
+// MulBitMatrix returns the k×k bit matrix of multiplication by c, where the
+// field has 2**k elements.  Viewing each element as a column vector of its
+// k bits, the product of the matrix with x is c*x.  Column j holds the bits of
+// c times the jth basis element, 1<<j.
+//
+// Multiplication by a constant is linear over GF(2), so a single matrix
+// replaces the log and exp tables, which is the basis of XOR-only
+// (bit-matrix) erasure coding.
+func (gf *GF) MulBitMatrix(c byte) BitMatrix {
+	k := int(gf.k)
+	m := newBitMatrix(k, k)
+	for j := 0; j < k; j++ {
+		p := gf.Mul(c, 1<<uint(j))
+		for i := 0; i < k; i++ {
+			if p&(1<<uint(i)) != 0 {
+				m.set(i, j)
+			}
+		}
+	}
+	return m
+}
+
+// BitMatrix returns the bit matrix obtained by replacing each entry c of m
+// with the k×k bit matrix of multiplication by c, so that an r×n matrix over
+// GF(2**k) becomes an rk×nk bit matrix.  It acts on vectors of field elements
+// expanded into bits, k bits per element, least significant bit first.
+func (m Matrix) BitMatrix() BitMatrix {
+	k := int(m.field.k)
+	bm := newBitMatrix(m.rows*k, m.cols*k)
+	blocks := make(map[byte]BitMatrix)
+	for i := 0; i < m.rows; i++ {
+		for j, c := range m.row(i) {
+			if c == 0 {
+				continue
+			}
+			block, ok := blocks[c]
+			if !ok {
+				block = m.field.MulBitMatrix(c)
+				blocks[c] = block
+			}
+			for bi := 0; bi < k; bi++ {
+				xorShifted(bm.row(i*k+bi), block.row(bi), uint(j*k))
+			}
+		}
+	}
+	return bm
+}
+
+// Rows returns the number of rows.
+func (m BitMatrix) Rows() int { return m.rows }
+
+// Cols returns the number of columns.
+func (m BitMatrix) Cols() int { return m.cols }
+
+// At returns the entry, 0 or 1, in row i and column j.
+func (m BitMatrix) At(i, j int) byte {
+	if i < 0 || i >= m.rows || j < 0 || j >= m.cols {
+		panic(ErrDimensionMismatch)
+	}
+	return byte(m.words[i*m.stride+j/64]>>uint(j%64)) & 1
+}
+
+// Row returns a copy of the packed row i.
+func (m BitMatrix) Row(i int) []uint64 {
+	if i < 0 || i >= m.rows {
+		panic(ErrDimensionMismatch)
+	}
+	return append([]uint64(nil), m.row(i)...)
+}
+
+// IsSquare returns true iff this matrix has as many rows as columns.
+func (m BitMatrix) IsSquare() bool { return m.rows == m.cols }
+
+// Add returns the sum of one or more bit matrices of the same shape.
+func (first BitMatrix) Add(rest ...BitMatrix) BitMatrix {
+	sum := first.clone()
+	for _, next := range rest {
+		if next.rows != first.rows || next.cols != first.cols {
+			panic(ErrDimensionMismatch)
+		}
+		for i, w := range next.words {
+			sum.words[i] ^= w
+		}
+	}
+	return sum
+}
+
+// Mul returns the product of one or more bit matrices.
+//
+// This is the Method of Four Russians: for each group of eight rows of the
+// right-hand operand, a table of all 256 of their sums is built, after which
+// each row of the left-hand operand needs one table lookup and one row XOR
+// per group, instead of up to eight.
+func (first BitMatrix) Mul(rest ...BitMatrix) BitMatrix {
+	prod := first
+	for _, next := range rest {
+		if prod.cols != next.rows {
+			panic(ErrDimensionMismatch)
+		}
+		out := newBitMatrix(prod.rows, next.cols)
+		table := make([]uint64, (1<<m4riBits)*next.stride)
+		for k0 := 0; k0 < next.rows; k0 += m4riBits {
+			n := next.rows - k0
+			if n > m4riBits {
+				n = m4riBits
+			}
+			next.sumTable(table, k0, n)
+			w, shift := k0/64, uint(k0%64)
+			for i := 0; i < prod.rows; i++ {
+				idx := int(prod.words[i*prod.stride+w]>>shift) & (1<<m4riBits - 1)
+				if idx == 0 {
+					continue
+				}
+				src := table[idx*next.stride : (idx+1)*next.stride]
+				for j, v := range src {
+					out.words[i*out.stride+j] ^= v
+				}
+			}
+		}
+		prod = out
+	}
+	return prod
+}
+
+// MulVec returns the product m*v of this matrix with the packed column vector
+// v, which holds (m.Cols()+63)/64 words.
+func (m BitMatrix) MulVec(v []uint64) []uint64 {
+	if len(v) != m.stride {
+		panic(ErrDimensionMismatch)
+	}
+	out := make([]uint64, (m.rows+63)/64)
+	for i := 0; i < m.rows; i++ {
+		var parity uint64
+		for j, w := range m.row(i) {
+			parity ^= w & v[j]
+		}
+		out[i/64] |= uint64(bits.OnesCount64(parity)&1) << uint(i%64)
+	}
+	return out
+}
+
+// MulShards computes the product of m with the shards in src, and stores it
+// in dst: each dst[i] is overwritten with the XOR of the src[j] for which
+// m[i][j] is 1.  This is how bit-matrix erasure codes encode and decode data
+// split into equal-sized packets, with no field arithmetic at all.
+//
+// There must be one src shard per column and one dst shard per row, all of
+// the same length; otherwise it panics with ErrDimensionMismatch.  The dst
+// shards must not overlap the src shards.
+func (m BitMatrix) MulShards(dst, src [][]byte) {
+	if len(src) != m.cols || len(dst) != m.rows {
+		panic(ErrDimensionMismatch)
+	}
+	n := -1
+	for _, shards := range [][][]byte{src, dst} {
+		for _, shard := range shards {
+			if n >= 0 && len(shard) != n {
+				panic(ErrDimensionMismatch)
+			}
+			n = len(shard)
+		}
+	}
+	for i, out := range dst {
+		for k := range out {
+			out[k] = 0
+		}
+		for w, word := range m.row(i) {
+			for word != 0 {
+				j := 64*w + bits.TrailingZeros64(word)
+				word &= word - 1
+				for k, v := range src[j] {
+					out[k] ^= v
+				}
+			}
+		}
+	}
+}
+
+// Transpose returns the transpose of this matrix.
+func (m BitMatrix) Transpose() BitMatrix {
+	t := newBitMatrix(m.cols, m.rows)
+	for i := 0; i < m.rows; i++ {
+		for w, word := range m.row(i) {
+			for word != 0 {
+				j := 64*w + bits.TrailingZeros64(word)
+				word &= word - 1
+				t.set(j, i)
+			}
+		}
+	}
+	return t
+}
+
+// RowEchelon returns the reduced row echelon form of this matrix, and its
+// pivot columns in increasing order.
+func (m BitMatrix) RowEchelon() (BitMatrix, []int) {
+	e := m.clone()
+	pivots := e.eliminate(true)
+	return e, pivots
+}
+
+// Rank returns the rank of this matrix.
+func (m BitMatrix) Rank() int {
+	return len(m.clone().eliminate(false))
+}
+
+// Inverse returns the inverse of this square matrix, or ErrSingular if it
+// has none.  It panics with ErrDimensionMismatch if the matrix is not square.
+func (m BitMatrix) Inverse() (BitMatrix, error) {
+	if !m.IsSquare() {
+		panic(ErrDimensionMismatch)
+	}
+	n := m.rows
+	aug := m.augment(BitIdentity(n))
+	if !leadingPivots(aug.eliminate(true), n) {
+		return BitMatrix{}, ErrSingular
+	}
+	return aug.columns(n, 2*n), nil
+}
+
+// Solve returns the packed solution x of m*x = b for a square matrix m, or
+// ErrSingular if m is singular.  It panics with ErrDimensionMismatch if m is
+// not square or b has the wrong length.
+func (m BitMatrix) Solve(b []uint64) ([]uint64, error) {
+	if !m.IsSquare() || len(b) != m.stride {
+		panic(ErrDimensionMismatch)
+	}
+	n := m.rows
+	col := newBitMatrix(n, 1)
+	for i := 0; i < n; i++ {
+		col.words[i] = b[i/64] >> uint(i%64) & 1
+	}
+	aug := m.augment(col)
+	if !leadingPivots(aug.eliminate(true), n) {
+		return nil, ErrSingular
+	}
+	return aug.columns(n, n+1).Transpose().Row(0), nil
+}
+
+// Nullspace returns a bit matrix whose columns form a basis of the null
+// space of m, that is, of the vectors x with m*x = 0.  It has m.Cols() rows
+// and m.Cols() - m.Rank() columns, as for Matrix.Nullspace.
+func (m BitMatrix) Nullspace() BitMatrix {
+	e, pivots := m.RowEchelon()
+	isPivot := make([]bool, m.cols)
+	for _, c := range pivots {
+		isPivot[c] = true
+	}
+	basis := newBitMatrix(m.cols, m.cols-len(pivots))
+	k := 0
+	for j := 0; j < m.cols; j++ {
+		if isPivot[j] {
+			continue
+		}
+		basis.set(j, k)
+		for r, c := range pivots {
+			if e.At(r, j) != 0 {
+				basis.set(c, k)
+			}
+		}
+		k++
+	}
+	return basis
+}
+
+// Compare defines a total order for bit matrices: -1 if a < b, 0 if a == b,
+// +1 if a > b.  Bit matrices are ordered by shape, then packed words in
+// row-major order.
+func (a BitMatrix) Compare(b BitMatrix) int {
+	switch {
+	case a.rows < b.rows:
+		return -1
+	case a.rows > b.rows:
+		return 1
+	case a.cols < b.cols:
+		return -1
+	case a.cols > b.cols:
+		return 1
+	}
+	for i, w := range a.words {
+		switch {
+		case w < b.words[i]:
+			return -1
+		case w > b.words[i]:
+			return 1
+		}
+	}
+	return 0
+}
+
+// Equal returns true iff a == b.
+func (a BitMatrix) Equal(b BitMatrix) bool {
+	return a.Compare(b) == 0
+}
+
+// Less returns true iff a < b.
+func (a BitMatrix) Less(b BitMatrix) bool {
+	return a.Compare(b) < 0
+}
+
+// GoString returns a Go-syntax representation of this matrix.
+func (m BitMatrix) GoString() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "NewBitMatrix(%d, %d", m.rows, m.cols)
+	for _, w := range m.words {
+		fmt.Fprintf(&buf, ", 0x%x", w)
+	}
+	buf.WriteByte(')')
+	return buf.String()
+}
+
+// String returns a human-readable representation of this matrix, with one
+// digit per entry and rows separated by semicolons, such as "[10; 11]".
+func (m BitMatrix) String() string {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i := 0; i < m.rows; i++ {
+		if i > 0 {
+			buf.WriteString("; ")
+		}
+		for j := 0; j < m.cols; j++ {
+			buf.WriteString(strconv.Itoa(int(m.At(i, j))))
+		}
+	}
+	buf.WriteByte(']')
+	return buf.String()
+}
+
+// eliminate brings m to row echelon form in place, or to reduced row echelon
+// form if reduced is set, and returns the pivot columns.
+//
+// This is the Method of Four Russians for inversion (M4RI).  The columns are
+// taken in groups of eight.  Within a group, pivot rows are found and reduced
+// against one another by ordinary elimination, looking only at the group's
+// bits of the candidate rows.  Then a table of all sums of the pivot rows
+// clears the group's pivot columns from every other row with one XOR each.
+func (m BitMatrix) eliminate(reduced bool) []int {
+	var pivots []int
+	table := make([]uint64, (1<<m4riBits)*m.stride)
+	groupPivots := make([]uint, 0, m4riBits)
+	r := 0
+	for c0 := 0; c0 < m.cols && r < m.rows; c0 += m4riBits {
+		w, shift := c0/64, uint(c0%64)
+		group := func(i int) uint {
+			return uint(m.words[i*m.stride+w]>>shift) & (1<<m4riBits - 1)
+		}
+		// The pivot rows of the group are r, r+1, ...; pivot row k has a
+		// 1 in column groupPivots[k] and 0 in the group's other pivot
+		// columns.
+		groupPivots = groupPivots[:0]
+		for c := uint(0); c < m4riBits && c0+int(c) < m.cols; c++ {
+			next := r + len(groupPivots)
+			if next == m.rows {
+				break
+			}
+			p := next
+			for ; p < m.rows; p++ {
+				g := group(p)
+				for k, b := range groupPivots {
+					if g&(1<<b) != 0 {
+						g ^= group(r + k)
+					}
+				}
+				if g&(1<<c) != 0 {
+					break
+				}
+			}
+			if p == m.rows {
+				continue
+			}
+			m.swapRows(next, p)
+			for k, b := range groupPivots {
+				if group(next)&(1<<b) != 0 {
+					m.xorRow(next, r+k)
+				}
+			}
+			for k := range groupPivots {
+				if group(r+k)&(1<<c) != 0 {
+					m.xorRow(r+k, next)
+				}
+			}
+			groupPivots = append(groupPivots, c)
+		}
+		n := len(groupPivots)
+		if n == 0 {
+			continue
+		}
+
+		m.sumTable(table, r, n)
+		start := r + n
+		if reduced {
+			start = 0
+		}
+		for i := start; i < m.rows; i++ {
+			if i >= r && i < r+n {
+				continue
+			}
+			g, idx := group(i), 0
+			for k, b := range groupPivots {
+				if g&(1<<b) != 0 {
+					idx |= 1 << uint(k)
+				}
+			}
+			if idx == 0 {
+				continue
+			}
+			// The pivot rows are zero before the group's word.
+			dst, src := m.row(i), table[idx*m.stride:(idx+1)*m.stride]
+			for k := w; k < m.stride; k++ {
+				dst[k] ^= src[k]
+			}
+		}
+		for _, b := range groupPivots {
+			pivots = append(pivots, c0+int(b))
+		}
+		r += n
+	}
+	return pivots
+}
+
+func newBitMatrix(rows, cols int) BitMatrix {
+	stride := (cols + 63) / 64
+	return BitMatrix{rows, cols, stride, make([]uint64, rows*stride)}
+}
+
+func (m BitMatrix) row(i int) []uint64 {
+	return m.words[i*m.stride : (i+1)*m.stride : (i+1)*m.stride]
+}
+
+func (m BitMatrix) set(i, j int) {
+	m.words[i*m.stride+j/64] |= 1 << uint(j%64)
+}
+
+// lastMask returns the mask of the valid bits in the last word of a row.
+func (m BitMatrix) lastMask() uint64 {
+	if m.cols%64 == 0 {
+		return ^uint64(0)
+	}
+	return 1<<uint(m.cols%64) - 1
+}
+
+func (m BitMatrix) swapRows(i, j int) {
+	if i == j {
+		return
+	}
+	a, b := m.row(i), m.row(j)
+	for k := range a {
+		a[k], b[k] = b[k], a[k]
+	}
+}
+
+func (m BitMatrix) xorRow(dst, src int) {
+	d, s := m.row(dst), m.row(src)
+	for k, v := range s {
+		d[k] ^= v
+	}
+}
+
+func (m BitMatrix) clone() BitMatrix {
+	return BitMatrix{m.rows, m.cols, m.stride, append([]uint64(nil), m.words...)}
+}
+
+// sumTable fills table with the sums of every subset of the n rows of m
+// starting at row k0: entry idx is the sum of the rows k0+b for the set bits
+// b of idx.
+func (m BitMatrix) sumTable(table []uint64, k0, n int) {
+	s := m.stride
+	for k := range table[:s] {
+		table[k] = 0
+	}
+	for idx := 1; idx < 1<<uint(n); idx++ {
+		low := idx & -idx
+		src := m.row(k0 + bits.TrailingZeros(uint(low)))
+		prev := table[(idx^low)*s : (idx^low+1)*s]
+		dst := table[idx*s : (idx+1)*s]
+		for k, v := range src {
+			dst[k] = prev[k] ^ v
+		}
+	}
+}
+
+// augment returns the bit matrix [m | b].
+func (m BitMatrix) augment(b BitMatrix) BitMatrix {
+	if b.rows != m.rows {
+		panic(ErrDimensionMismatch)
+	}
+	aug := newBitMatrix(m.rows, m.cols+b.cols)
+	for i := 0; i < m.rows; i++ {
+		copy(aug.row(i), m.row(i))
+		xorShifted(aug.row(i), b.row(i), uint(m.cols))
+	}
+	return aug
+}
+
+// columns returns the sub-matrix formed from columns lo to hi-1.
+func (m BitMatrix) columns(lo, hi int) BitMatrix {
+	sub := newBitMatrix(m.rows, hi-lo)
+	w, shift := lo/64, uint(lo%64)
+	for i := 0; i < m.rows; i++ {
+		src, dst := m.row(i)[w:], sub.row(i)
+		for k := range dst {
+			v := src[k] >> shift
+			if shift > 0 && k+1 < len(src) {
+				v |= src[k+1] << (64 - shift)
+			}
+			dst[k] = v
+		}
+		dst[len(dst)-1] &= sub.lastMask()
+	}
+	return sub
+}
@@ -0,0 +1,264 @@
+package galoisfield
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestBitMatrix_String(t *testing.T) {
+	type testrow struct {
+		input BitMatrix
+		str   string
+		gostr string
+	}
+	for idx, row := range []testrow{
+		testrow{NewBitMatrix(0, 0), "[]", "NewBitMatrix(0, 0)"},
+		testrow{NewBitMatrix(2, 2, 0x1, 0x3), "[10; 11]", "NewBitMatrix(2, 2, 0x1, 0x3)"},
+		testrow{BitIdentity(3).Transpose(), "[100; 010; 001]", "NewBitMatrix(3, 3, 0x1, 0x2, 0x4)"},
+		testrow{NewBitMatrix(1, 3, 0x6), "[011]", "NewBitMatrix(1, 3, 0x6)"},
+	} {
+		if actual := row.input.String(); actual != row.str {
+			t.Errorf("[%d] String: expected %q, got %q", idx, row.str, actual)
+		}
+		if actual := row.input.GoString(); actual != row.gostr {
+			t.Errorf("[%d] GoString: expected %q, got %q", idx, row.gostr, actual)
+		}
+	}
+}
+
+func TestBitMatrix_Mul(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for trial := 0; trial < 32; trial++ {
+		r, k, c := 1+prng.Intn(150), 1+prng.Intn(150), 1+prng.Intn(150)
+		a, b := randomBitMatrix(prng, r, k), randomBitMatrix(prng, k, c)
+		ab := a.Mul(b)
+		for i := 0; i < r; i++ {
+			for j := 0; j < c; j++ {
+				var sum byte
+				for l := 0; l < k; l++ {
+					sum ^= a.At(i, l) & b.At(l, j)
+				}
+				if ab.At(i, j) != sum {
+					t.Fatalf("%d×%d times %d×%d at %d,%d: expected %d, got %d",
+						r, k, k, c, i, j, sum, ab.At(i, j))
+				}
+			}
+		}
+		if !ab.Transpose().Equal(b.Transpose().Mul(a.Transpose())) {
+			t.Errorf("(AB)^T != B^T A^T for %d×%d times %d×%d", r, k, k, c)
+		}
+		v := randomBitMatrix(prng, 1, c).Row(0)
+		if !equalWords(ab.MulVec(v), a.MulVec(b.MulVec(v))) {
+			t.Errorf("MulVec is not associative for %d×%d times %d×%d", r, k, k, c)
+		}
+		if !a.Add(a).Equal(NewBitMatrix(r, k)) || !a.Mul(BitIdentity(k)).Equal(a) {
+			t.Errorf("Add/identity fail for %#v", a)
+		}
+	}
+}
+
+func TestBitMatrix_Inverse(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for trial := 0; trial < 64; trial++ {
+		n := prng.Intn(140)
+		m := randomBitMatrix(prng, n, n)
+		inv, err := m.Inverse()
+		b := randomBitMatrix(prng, 1, n).Row(0)
+		x, serr := m.Solve(b)
+		rank := m.Rank()
+		if rank < n {
+			if err != ErrSingular || serr != ErrSingular {
+				t.Errorf("expected %d×%d matrix of rank %d to be singular, got %v, %v", n, n, rank, err, serr)
+			}
+			continue
+		}
+		if err != nil || serr != nil {
+			t.Errorf("expected %d×%d matrix to be invertible, got %v, %v", n, n, err, serr)
+			continue
+		}
+		if !m.Mul(inv).Equal(BitIdentity(n)) || !inv.Mul(m).Equal(BitIdentity(n)) {
+			t.Errorf("%d×%d: M * M**-1 is not the identity", n, n)
+		}
+		if !equalWords(m.MulVec(x), b) {
+			t.Errorf("%d×%d: M * %x != %x", n, n, x, b)
+		}
+	}
+}
+
+func TestBitMatrix_Rank(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range []*GF{DefaultGF4, DefaultGF16} {
+		for trial := 0; trial < 32; trial++ {
+			// A product of thin matrices has deficient rank, which
+			// matches the rank of its expansion over GF(2).
+			r, k, c := 1+prng.Intn(40), prng.Intn(20), 1+prng.Intn(40)
+			a, b := randomBitMatrix(prng, r, k), randomBitMatrix(prng, k, c)
+			m := a.Mul(b)
+			e, pivots := m.RowEchelon()
+			rank := m.Rank()
+			if rank != len(pivots) || rank > k {
+				t.Errorf("%d×%d of inner dimension %d: rank %d, pivots %v", r, c, k, rank, pivots)
+			}
+			if asGF := bitMatrixAsMatrix(field, m); asGF.Rank() != rank {
+				t.Errorf("%d×%d: expected rank %d, got %d", r, c, asGF.Rank(), rank)
+			}
+			if expected, _ := bitMatrixAsMatrix(field, m).ReducedRowEchelon(); !bitMatrixAsMatrix(field, e).Equal(expected) {
+				t.Errorf("%d×%d: expected RREF (%v), got (%v)", r, c, expected, e)
+			}
+			n := m.Nullspace()
+			if n.Rows() != c || n.Cols() != c-rank || n.Rank() != c-rank || !m.Mul(n).Equal(NewBitMatrix(r, c-rank)) {
+				t.Errorf("%d×%d: (%v) is not a nullspace basis of (%v)", r, c, n, m)
+			}
+		}
+	}
+}
+
+func TestGF_MulBitMatrix(t *testing.T) {
+	for _, field := range fields {
+		q := field.Size()
+		for c := uint(0); c < q; c += 1 + q/16 {
+			m := field.MulBitMatrix(byte(c))
+			for x := uint(0); x < q; x++ {
+				if y := m.MulVec([]uint64{uint64(x)}); y[0] != uint64(field.Mul(byte(c), byte(x))) {
+					t.Errorf("%v: %d * %d: expected %d, got %d", field, c, x, field.Mul(byte(c), byte(x)), y[0])
+				}
+			}
+			if c > 1 {
+				d := byte(c - 1)
+				if !m.Mul(field.MulBitMatrix(d)).Equal(field.MulBitMatrix(field.Mul(byte(c), d))) {
+					t.Errorf("%v: bit matrices of %d and %d do not compose", field, c, d)
+				}
+			}
+		}
+	}
+
+	// An expanded matrix acts on expanded vectors, and MulShards agrees with
+	// Matrix.MulShards on packets of bits.
+	prng := rand.New(rand.NewSource(42))
+	field := DefaultGF16
+	m := randomMatrix(prng, field, 3, 5)
+	bm := m.BitMatrix()
+	if bm.Rows() != 12 || bm.Cols() != 20 {
+		t.Fatalf("expected a 12×20 bit matrix, got %d×%d", bm.Rows(), bm.Cols())
+	}
+	v := randomCoefficients(prng, 16, 5)
+	var packed uint64
+	for j, x := range v {
+		packed |= uint64(x) << uint(4*j)
+	}
+	out := bm.MulVec([]uint64{packed})[0]
+	for i, y := range m.MulVec(v) {
+		if byte(out>>uint(4*i))&15 != y {
+			t.Errorf("expanded product at %d: expected %d, got %d", i, y, byte(out>>uint(4*i))&15)
+		}
+	}
+	sq := randomMatrix(prng, field, 4, 4)
+	if inv, err := sq.Inverse(); err == nil {
+		if binv, err := sq.BitMatrix().Inverse(); err != nil || !binv.Equal(inv.BitMatrix()) {
+			t.Errorf("inverse of the expansion of (%v) is not the expansion of (%v): %v", sq, inv, err)
+		}
+	}
+}
+
+func TestBitMatrix_MulShards(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	m := randomBitMatrix(prng, 3, 70)
+	src := make([][]byte, 70)
+	for i := range src {
+		src[i] = randomCoefficients(prng, 256, 10)
+	}
+	dst := make([][]byte, 3)
+	for i := range dst {
+		dst[i] = randomCoefficients(prng, 256, 10)
+	}
+	m.MulShards(dst, src)
+	for i := range dst {
+		expected := make([]byte, 10)
+		for j := range src {
+			if m.At(i, j) != 0 {
+				for k, v := range src[j] {
+					expected[k] ^= v
+				}
+			}
+		}
+		if !equalBytes(dst[i], expected) {
+			t.Errorf("shard %d: expected %v, got %v", i, expected, dst[i])
+		}
+	}
+}
+
+func TestBitMatrix_panics(t *testing.T) {
+	type testrow struct {
+		fn       func()
+		expected error
+	}
+	a := NewBitMatrix(2, 3)
+	for idx, row := range []testrow{
+		testrow{func() { NewBitMatrix(1, 3, 0x8) }, ErrDimensionMismatch},
+		testrow{func() { NewBitMatrix(1, 3, 1, 1) }, ErrDimensionMismatch},
+		testrow{func() { a.Mul(a) }, ErrDimensionMismatch},
+		testrow{func() { a.Add(a.Transpose()) }, ErrDimensionMismatch},
+		testrow{func() { a.MulVec(nil) }, ErrDimensionMismatch},
+		testrow{func() { a.Inverse() }, ErrDimensionMismatch},
+		testrow{func() { BitIdentity(2).Solve(nil) }, ErrDimensionMismatch},
+		testrow{func() { a.At(0, 3) }, ErrDimensionMismatch},
+		testrow{func() { a.MulShards([][]byte{{1}, {1}}, [][]byte{{1}, {1, 2}, {1}}) }, ErrDimensionMismatch},
+	} {
+		if e := panicValue(row.fn); e != row.expected {
+			t.Errorf("[%d] expected panic(%v), got %v", idx, row.expected, e)
+		}
+	}
+}
+
+func randomBitMatrix(prng *rand.Rand, rows, cols int) BitMatrix {
+	m := newBitMatrix(rows, cols)
+	for i := range m.words {
+		m.words[i] = uint64(prng.Int63()) ^ uint64(prng.Int63())<<1
+	}
+	for i := 0; i < rows && cols > 0; i++ {
+		m.row(i)[m.stride-1] &= m.lastMask()
+	}
+	return m
+}
+
+// bitMatrixAsMatrix returns the matrix with the same entries, 0 and 1, over
+// the given field.
+func bitMatrixAsMatrix(field *GF, m BitMatrix) Matrix {
+	out := NewMatrix(field, m.Rows(), m.Cols())
+	for i := 0; i < m.Rows(); i++ {
+		for j := 0; j < m.Cols(); j++ {
+			out.data[i*m.Cols()+j] = m.At(i, j)
+		}
+	}
+	return out
+}
+
+func equalWords(a, b []uint64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func BenchmarkBitMatrix_Mul(b *testing.B) {
+	prng := rand.New(rand.NewSource(42))
+	m := randomBitMatrix(prng, 512, 512)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Mul(m)
+	}
+}
+
+func BenchmarkBitMatrix_Inverse(b *testing.B) {
+	prng := rand.New(rand.NewSource(42))
+	m := randomBitMatrix(prng, 512, 512)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Inverse()
+	}
+}
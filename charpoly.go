@@ -0,0 +1,221 @@
+package galoisfield
+
+import (
+	"math/big"
+)
+
+// CompanionMatrix returns the companion matrix of the monic polynomial
+// p = c_0 + c_1 x + ... + x**n, made monic if necessary: the n×n matrix
+// with ones below the diagonal and -c_0, ..., -c_{n-1} in the last column.
+// It panics with ErrDivByZero if p is zero.
+//
+// The companion matrix represents multiplication by x modulo p in the basis
+// 1, x, ..., x**(n-1), so its characteristic and minimal polynomials are
+// both p.  For a connection polynomial of an LFSR, its transpose steps the
+// register's state.
+func CompanionMatrix(p Polynomial) Matrix {
+	if p.IsZero() {
+		panic(ErrDivByZero)
+	}
+	p = p.Monic()
+	n := int(p.Degree())
+	m := NewMatrix(p.field, n, n)
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			m.data[i*n+i-1] = 1
+		}
+		m.data[i*n+n-1] = p.coefficients[i]
+	}
+	return m
+}
+
+// CharacteristicPolynomial returns det(xI - m), a monic polynomial of degree
+// n.  It panics with ErrDimensionMismatch if the matrix is not square.
+//
+// The matrix is first reduced to upper Hessenberg form H by similarity
+// transforms, which takes O(n**3) field operations.  The characteristic
+// polynomials p_k of the leading k×k blocks of H then satisfy
+//
+//	p_k = (x - h_{k,k}) p_{k-1} - Σ_{i<k} h_{i,k} (∏_{i<j≤k} h_{j,j-1}) p_{i-1}
+//
+// counting from 1, with p_0 = 1.
+func (m Matrix) CharacteristicPolynomial() Polynomial {
+	if !m.IsSquare() {
+		panic(ErrDimensionMismatch)
+	}
+	field := m.field
+	h := m.hessenberg()
+	n := h.rows
+	p := make([]Polynomial, n+1)
+	p[0] = NewPolynomial(field, 1)
+	for k := 1; k <= n; k++ {
+		p[k] = p[k-1].Mul(NewPolynomial(field, h.data[(k-1)*n+k-1], 1))
+		var t byte = 1
+		for i := k - 1; i > 0; i-- {
+			t = field.Mul(t, h.data[i*n+i-1])
+			if t == 0 {
+				break
+			}
+			p[k] = p[k].Add(p[i-1].Scale(field.Mul(t, h.data[(i-1)*n+k-1])))
+		}
+	}
+	return p[n]
+}
+
+// hessenberg returns an upper Hessenberg matrix similar to the square matrix
+// m, that is, one which is zero below the first subdiagonal.
+func (m Matrix) hessenberg() Matrix {
+	field := m.field
+	h := m.clone()
+	n := h.rows
+	for j := 0; j+2 < n; j++ {
+		p := j + 1
+		for p < n && h.data[p*n+j] == 0 {
+			p++
+		}
+		if p == n {
+			continue
+		}
+		// Swapping rows p and j+1, and then the same columns, is a
+		// similarity transform.
+		h.swapRows(p, j+1)
+		for i := 0; i < n; i++ {
+			row := h.row(i)
+			row[p], row[j+1] = row[j+1], row[p]
+		}
+		inv := field.Inv(h.data[(j+1)*n+j])
+		for i := j + 2; i < n; i++ {
+			u := field.Mul(h.data[i*n+j], inv)
+			if u == 0 {
+				continue
+			}
+			// Subtract u times row j+1 from row i, then add u times
+			// column i to column j+1, to undo the change of basis.
+			addScaled(field, h.row(i)[j:], h.row(j + 1)[j:], u)
+			for r := 0; r < n; r++ {
+				row := h.row(r)
+				row[j+1] ^= field.Mul(u, row[i])
+			}
+		}
+	}
+	return h
+}
+
+// MinimalPolynomial returns the monic polynomial μ of least degree such that
+// μ(m) = 0.  It divides the characteristic polynomial, and has the same
+// irreducible factors.  It panics with ErrDimensionMismatch if the matrix is
+// not square.
+//
+// μ is the least common multiple of the annihilators of the unit vectors.
+// Each annihilator is found as the first linear dependency among the vectors
+// v, m*v, m**2*v, ...; the unit vectors which μ already annihilates are
+// skipped, and the others contribute only the factors not yet found.
+func (m Matrix) MinimalPolynomial() Polynomial {
+	if !m.IsSquare() {
+		panic(ErrDimensionMismatch)
+	}
+	n := m.rows
+	mu := NewPolynomial(m.field, 1)
+	for i := 0; i < n; i++ {
+		v := make([]byte, n)
+		v[i] = 1
+		if w := mu.evaluateVector(m, v); !isZeroVector(w) {
+			mu = mu.Mul(m.annihilator(w))
+		}
+	}
+	return mu
+}
+
+// annihilator returns the monic polynomial a of least degree such that
+// a(m)*v = 0.
+func (m Matrix) annihilator(v []byte) Polynomial {
+	n := m.rows
+	// Each row holds a vector of the Krylov space, followed by the
+	// coefficients of the polynomial in m which yields it from v.
+	rr := NewRowReducer(m.field, 2*n+1)
+	row := make([]byte, 2*n+1)
+	u := v
+	for d := 0; ; d++ {
+		copy(row, u)
+		for j := n; j < len(row); j++ {
+			row[j] = 0
+		}
+		row[n+d] = 1
+		rr.Add(row)
+		// The pivots are sorted, so a dependency, a reduced row whose
+		// vector part is zero, comes last.
+		if last := len(rr.pivots) - 1; rr.pivots[last] >= n {
+			return NewPolynomial(m.field, rr.rows[last][n:]...).Monic()
+		}
+		u = m.MulVec(u)
+	}
+}
+
+// Pow returns m**e for a square matrix m.  By convention, m**0 is the
+// identity.  A negative exponent raises the inverse of m, and returns
+// ErrSingular if m is singular.  It panics with ErrDimensionMismatch if the
+// matrix is not square.
+//
+// Left-to-right square-and-multiply takes O(n**3 log e) field operations, so
+// for example an LFSR may be jumped forward by 2**40 steps in 40 squarings.
+func (m Matrix) Pow(e *big.Int) (Matrix, error) {
+	if !m.IsSquare() {
+		panic(ErrDimensionMismatch)
+	}
+	base := m
+	if e.Sign() < 0 {
+		var err error
+		if base, err = m.Inverse(); err != nil {
+			return Matrix{}, err
+		}
+		e = new(big.Int).Neg(e)
+	}
+	result := Identity(m.field, m.rows)
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		result = result.Mul(result)
+		if e.Bit(i) != 0 {
+			result = result.Mul(base)
+		}
+	}
+	return result, nil
+}
+
+// EvaluateMatrix returns the matrix a(m), by Horner's rule.  It panics with
+// ErrDimensionMismatch if m is not square, or with ErrIncompatibleFields if a
+// and m are drawn from different fields.
+func (a Polynomial) EvaluateMatrix(m Matrix) Matrix {
+	if a.field != m.field {
+		panic(ErrIncompatibleFields)
+	}
+	if !m.IsSquare() {
+		panic(ErrDimensionMismatch)
+	}
+	n := m.rows
+	result := NewMatrix(m.field, n, n)
+	for i := len(a.coefficients) - 1; i >= 0; i-- {
+		result = result.Mul(m)
+		for j := 0; j < n; j++ {
+			result.data[j*n+j] ^= a.coefficients[i]
+		}
+	}
+	return result
+}
+
+// evaluateVector returns a(m)*v, by Horner's rule.
+func (a Polynomial) evaluateVector(m Matrix, v []byte) []byte {
+	result := make([]byte, len(v))
+	for i := len(a.coefficients) - 1; i >= 0; i-- {
+		result = m.MulVec(result)
+		addScaled(m.field, result, v, a.coefficients[i])
+	}
+	return result
+}
+
+func isZeroVector(v []byte) bool {
+	for _, x := range v {
+		if x != 0 {
+			return false
+		}
+	}
+	return true
+}
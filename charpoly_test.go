@@ -0,0 +1,161 @@
+package galoisfield
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func TestCompanionMatrix(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		for trial := 0; trial < 8; trial++ {
+			n := prng.Intn(7)
+			p := NewPolynomial(field, append(randomCoefficients(prng, int(field.Size()), n), 1)...)
+			c := CompanionMatrix(p.Scale(2))
+			if c.Rows() != n || !c.IsSquare() {
+				t.Errorf("%v: expected a %d×%d companion matrix for (%v), got (%v)", field, n, n, p, c)
+				continue
+			}
+			if actual := c.CharacteristicPolynomial(); !actual.Equal(p) {
+				t.Errorf("%v: charpoly of (%v): expected (%v), got (%v)", field, c, p, actual)
+			}
+			if actual := c.MinimalPolynomial(); !actual.Equal(p) {
+				t.Errorf("%v: minpoly of (%v): expected (%v), got (%v)", field, c, p, actual)
+			}
+			// Multiplication by x modulo p.
+			if n > 0 {
+				a := NewPolynomial(field, randomCoefficients(prng, int(field.Size()), n)...)
+				expected := expand(n, a.Mul(NewPolynomial(field, 0, 1)).Mod(p).coefficients)
+				if actual := c.MulVec(expand(n, a.coefficients)); !equalBytes(actual, expected) {
+					t.Errorf("%v: (%v) * (%v): expected %v, got %v", field, c, a, expected, actual)
+				}
+			}
+		}
+	}
+}
+
+func TestMatrix_CharacteristicPolynomial(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		for trial := 0; trial < 16; trial++ {
+			n := prng.Intn(7)
+			m := randomMatrix(prng, field, n, n)
+			if trial%2 == 1 {
+				// Sparse matrices exercise the zero pivots of the
+				// Hessenberg reduction.
+				for i := range m.data {
+					if prng.Intn(3) > 0 {
+						m.data[i] = 0
+					}
+				}
+			}
+			chi := m.CharacteristicPolynomial()
+			if chi.Degree() != uint(n) || chi.LeadingCoefficient() != 1 {
+				t.Errorf("%v: charpoly of (%v) is (%v)", field, m, chi)
+			}
+			zero := NewMatrix(field, n, n)
+			if !chi.EvaluateMatrix(m).Equal(zero) {
+				t.Errorf("%v: (%v) does not satisfy its charpoly (%v)", field, m, chi)
+			}
+			if chi.Coefficient(0) != m.Determinant() {
+				t.Errorf("%v: charpoly of (%v) is (%v), but det is %d", field, m, chi, m.Determinant())
+			}
+			if !m.Transpose().CharacteristicPolynomial().Equal(chi) {
+				t.Errorf("%v: charpoly of the transpose of (%v) differs", field, m)
+			}
+
+			mu := m.MinimalPolynomial()
+			if !mu.EvaluateMatrix(m).Equal(zero) || mu.LeadingCoefficient() != 1 {
+				t.Errorf("%v: (%v) does not satisfy its minpoly (%v)", field, m, mu)
+			}
+			if _, r := chi.DivMod(mu); !r.IsZero() {
+				t.Errorf("%v: minpoly (%v) does not divide charpoly (%v)", field, mu, chi)
+			}
+			// I, m, ..., m**(d-1) are independent.
+			d := int(mu.Degree())
+			powers := NewMatrix(field, d, n*n)
+			power := Identity(field, n)
+			for i := 0; i < d; i++ {
+				copy(powers.row(i), power.data)
+				power = power.Mul(m)
+			}
+			if powers.Rank() != d {
+				t.Errorf("%v: minpoly (%v) of (%v) is not minimal", field, mu, m)
+			}
+		}
+	}
+
+	for idx, row := range []struct {
+		m       Matrix
+		chi, mu Polynomial
+	}{
+		{NewMatrix(nil, 0, 0), NewPolynomial(nil, 1), NewPolynomial(nil, 1)},
+		{Identity(nil, 3), NewPolynomial(nil, 1, 1, 1, 1), NewPolynomial(nil, 1, 1)},
+		{NewMatrix(nil, 3, 3), NewPolynomial(nil, 0, 0, 0, 1), NewPolynomial(nil, 0, 1)},
+		{NewMatrix(nil, 2, 2, 0, 1, 0, 0), NewPolynomial(nil, 0, 0, 1), NewPolynomial(nil, 0, 0, 1)},
+	} {
+		if chi := row.m.CharacteristicPolynomial(); !chi.Equal(row.chi) {
+			t.Errorf("[%d] charpoly: expected (%v), got (%v)", idx, row.chi, chi)
+		}
+		if mu := row.m.MinimalPolynomial(); !mu.Equal(row.mu) {
+			t.Errorf("[%d] minpoly: expected (%v), got (%v)", idx, row.mu, mu)
+		}
+	}
+}
+
+func TestMatrix_Pow(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		m := randomMatrix(prng, field, 4, 4)
+		expected := Identity(field, 4)
+		for e := int64(0); e < 10; e++ {
+			if actual, err := m.Pow(big.NewInt(e)); err != nil || !actual.Equal(expected) {
+				t.Errorf("%v: (%v)**%d: expected (%v), got (%v), %v", field, m, e, expected, actual, err)
+			}
+			expected = expected.Mul(m)
+		}
+		inv, err := m.Pow(big.NewInt(-3))
+		if cube, _ := m.Pow(big.NewInt(3)); m.Determinant() != 0 && (err != nil || !inv.Mul(cube).Equal(Identity(field, 4))) {
+			t.Errorf("%v: (%v)**-3 = (%v), %v", field, m, inv, err)
+		}
+	}
+
+	// Jump an LFSR: C**e = (x**e mod p)(C), and x has order dividing
+	// q**d - 1 modulo an irreducible p of degree d.
+	p := LowWeightIrreducible(Poly410_g2, 5)
+	c := CompanionMatrix(p)
+	e := new(big.Int).Lsh(big.NewInt(1), 40)
+	if jump, _ := c.Pow(e); !jump.Equal(XPowMod(e, p).EvaluateMatrix(c)) {
+		t.Errorf("(%v)**(2**40) != (x**(2**40) mod (%v))(C)", c, p)
+	}
+	order := new(big.Int).Lsh(big.NewInt(1), 20)
+	order.Sub(order, big.NewInt(1))
+	if one, _ := c.Pow(order); !one.Equal(Identity(Poly410_g2, 5)) {
+		t.Errorf("(%v)**(16**5 - 1) = (%v), expected the identity", c, one)
+	}
+
+	if _, err := NewMatrix(nil, 2, 2, 1, 1, 1, 1).Pow(big.NewInt(-1)); err != ErrSingular {
+		t.Errorf("expected %v, got %v", ErrSingular, err)
+	}
+}
+
+func TestCharpoly_panics(t *testing.T) {
+	type testrow struct {
+		fn       func()
+		expected error
+	}
+	a := NewMatrix(nil, 2, 3)
+	for idx, row := range []testrow{
+		testrow{func() { CompanionMatrix(NewPolynomial(nil)) }, ErrDivByZero},
+		testrow{func() { a.CharacteristicPolynomial() }, ErrDimensionMismatch},
+		testrow{func() { a.MinimalPolynomial() }, ErrDimensionMismatch},
+		testrow{func() { a.Pow(big.NewInt(2)) }, ErrDimensionMismatch},
+		testrow{func() { NewPolynomial(nil, 1).EvaluateMatrix(a) }, ErrDimensionMismatch},
+		testrow{func() { NewPolynomial(Poly210_g2, 1).EvaluateMatrix(Identity(nil, 1)) }, ErrIncompatibleFields},
+	} {
+		if e := panicValue(row.fn); e != row.expected {
+			t.Errorf("[%d] expected panic(%v), got %v", idx, row.expected, e)
+		}
+	}
+}
@@ -0,0 +1,321 @@
+package galoisfield
+
+// ReducedRowEchelon returns the reduced row echelon form of this matrix and
+// its pivot columns, in increasing order.  Each pivot column is zero except
+// for a single 1, in the row with the same index as the pivot, and the rank
+// is the number of pivots.
+func (m Matrix) ReducedRowEchelon() (Matrix, []int) {
+	e := m.clone()
+	pivots := e.eliminate(true)
+	return e, pivots
+}
+
+// Nullspace returns a matrix whose columns form a basis of the null space
+// of m, that is, of the vectors x with m*x = 0.  It has m.Cols() rows and
+// m.Cols() - m.Rank() columns, so that m.Mul(m.Nullspace()) is zero.
+//
+// There is one basis vector per non-pivot column j of the reduced row
+// echelon form: it has a 1 in position j, and the entries of column j in the
+// pivot positions (negated, but negation is the identity in characteristic
+// 2).
+func (m Matrix) Nullspace() Matrix {
+	e, pivots := m.ReducedRowEchelon()
+	isPivot := make([]bool, m.cols)
+	for _, c := range pivots {
+		isPivot[c] = true
+	}
+	basis := NewMatrix(m.field, m.cols, m.cols-len(pivots))
+	k := 0
+	for j := 0; j < m.cols; j++ {
+		if isPivot[j] {
+			continue
+		}
+		basis.data[j*basis.cols+k] = 1
+		for r, c := range pivots {
+			basis.data[c*basis.cols+k] = e.data[r*e.cols+j]
+		}
+		k++
+	}
+	return basis
+}
+
+// ColumnSpace returns a matrix whose columns form a basis of the column
+// space of m: the columns of m in which row reduction finds a pivot.  It
+// has m.Rows() rows and m.Rank() columns.
+func (m Matrix) ColumnSpace() Matrix {
+	pivots := m.clone().eliminate(false)
+	// A nil slice would select every column, so pass an empty one instead.
+	return m.SubMatrix(nil, append([]int{}, pivots...))
+}
+
+// LU is the PLU decomposition of a non-singular square matrix A: a
+// permutation matrix P, a unit lower triangular matrix L and an upper
+// triangular matrix U, such that P*A = L*U.
+//
+// Computing the decomposition takes O(n**3) field operations, after which
+// each system A*x = b may be solved in O(n**2).  An LU is immutable, and may
+// be used concurrently.
+type LU struct {
+	field *GF
+	n     int
+	perm  []int  // row i of P*A is row perm[i] of A
+	lu    Matrix // L below the diagonal, U on and above it
+}
+
+// LU returns the PLU decomposition of this square matrix, or ErrSingular if
+// it is singular.  It panics with ErrDimensionMismatch if the matrix is not
+// square.
+func (m Matrix) LU() (*LU, error) {
+	if !m.IsSquare() {
+		panic(ErrDimensionMismatch)
+	}
+	field := m.field
+	n := m.rows
+	a := m.clone()
+	perm := columnRange(0, n)
+	for k := 0; k < n; k++ {
+		p := k
+		for p < n && a.data[p*n+k] == 0 {
+			p++
+		}
+		if p == n {
+			return nil, ErrSingular
+		}
+		a.swapRows(k, p)
+		perm[k], perm[p] = perm[p], perm[k]
+		pivot := a.row(k)
+		inv := field.Inv(pivot[k])
+		for i := k + 1; i < n; i++ {
+			row := a.row(i)
+			if row[k] == 0 {
+				continue
+			}
+			l := field.Mul(row[k], inv)
+			row[k] = l
+			addScaled(field, row[k+1:], pivot[k+1:], l)
+		}
+	}
+	return &LU{field, n, perm, a}, nil
+}
+
+// Field returns the Galois field of the decomposed matrix.
+func (f *LU) Field() *GF { return f.field }
+
+// Size returns the number of rows and columns of the decomposed matrix.
+func (f *LU) Size() int { return f.n }
+
+// Perm returns the row permutation: row i of P*A is row Perm()[i] of A.
+func (f *LU) Perm() []int { return append([]int(nil), f.perm...) }
+
+// P returns the permutation matrix P.
+func (f *LU) P() Matrix {
+	p := NewMatrix(f.field, f.n, f.n)
+	for i, j := range f.perm {
+		p.data[i*f.n+j] = 1
+	}
+	return p
+}
+
+// L returns the unit lower triangular matrix L.
+func (f *LU) L() Matrix {
+	l := NewMatrix(f.field, f.n, f.n)
+	for i := 0; i < f.n; i++ {
+		copy(l.row(i), f.lu.row(i)[:i])
+		l.data[i*f.n+i] = 1
+	}
+	return l
+}
+
+// U returns the upper triangular matrix U.
+func (f *LU) U() Matrix {
+	u := NewMatrix(f.field, f.n, f.n)
+	for i := 0; i < f.n; i++ {
+		copy(u.row(i)[i:], f.lu.row(i)[i:])
+	}
+	return u
+}
+
+// Determinant returns the determinant of the decomposed matrix, the product
+// of the diagonal of U.  In characteristic 2, P does not affect the sign.
+func (f *LU) Determinant() byte {
+	var det byte = 1
+	for i := 0; i < f.n; i++ {
+		det = f.field.Mul(det, f.lu.data[i*f.n+i])
+	}
+	return det
+}
+
+// Solve returns the solution x of A*x = b.  It panics with
+// ErrDimensionMismatch if b has the wrong length.
+func (f *LU) Solve(b []byte) []byte {
+	if len(b) != f.n {
+		panic(ErrDimensionMismatch)
+	}
+	field := f.field
+	x := make([]byte, f.n)
+	for i, p := range f.perm {
+		x[i] = b[p]
+	}
+	// Forward substitution, L*y = P*b.
+	for i := 0; i < f.n; i++ {
+		row := f.lu.row(i)
+		for j := 0; j < i; j++ {
+			x[i] ^= field.Mul(row[j], x[j])
+		}
+	}
+	// Back substitution, U*x = y.
+	for i := f.n - 1; i >= 0; i-- {
+		row := f.lu.row(i)
+		for j := i + 1; j < f.n; j++ {
+			x[i] ^= field.Mul(row[j], x[j])
+		}
+		x[i] = field.Div(x[i], row[i])
+	}
+	return x
+}
+
+// SolveMatrix returns the solution X of A*X = B, solving for every column
+// of B at once.  It panics with ErrIncompatibleFields if B is drawn from a
+// different field, or with ErrDimensionMismatch if B has the wrong number of
+// rows.
+func (f *LU) SolveMatrix(b Matrix) Matrix {
+	f.lu.checkField(b)
+	if b.rows != f.n {
+		panic(ErrDimensionMismatch)
+	}
+	field := f.field
+	x := b.SubMatrix(f.perm, nil)
+	for i := 0; i < f.n; i++ {
+		dst := x.row(i)
+		for j, l := range f.lu.row(i)[:i] {
+			addScaled(field, dst, x.row(j), l)
+		}
+	}
+	for i := f.n - 1; i >= 0; i-- {
+		row, dst := f.lu.row(i), x.row(i)
+		for j := i + 1; j < f.n; j++ {
+			addScaled(field, dst, x.row(j), row[j])
+		}
+		if inv := field.Inv(row[i]); inv != 1 {
+			for k, v := range dst {
+				dst[k] = field.Mul(v, inv)
+			}
+		}
+	}
+	return x
+}
+
+// Inverse returns the inverse of the decomposed matrix.
+func (f *LU) Inverse() Matrix {
+	return f.SolveMatrix(Identity(f.field, f.n))
+}
+
+// RowReducer maintains the reduced row echelon form of a growing set of
+// rows, so that the rank can be tracked as rows arrive one at a time.  This
+// is the core of online decoders for fountain and network codes, which
+// accept coded symbols until the received rows reach full rank.
+//
+// Each Add costs O(rank * cols) field operations.
+type RowReducer struct {
+	field  *GF
+	cols   int
+	rows   [][]byte // reduced, sorted by pivot
+	pivots []int
+}
+
+// NewRowReducer returns a RowReducer, of rank zero, for rows of the given
+// length.  If field is nil, Default is used.
+func NewRowReducer(field *GF, cols int) *RowReducer {
+	if field == nil {
+		field = Default
+	}
+	if cols < 0 {
+		panic(ErrDimensionMismatch)
+	}
+	return &RowReducer{field: field, cols: cols}
+}
+
+// Field returns the Galois field from which the rows are drawn.
+func (rr *RowReducer) Field() *GF { return rr.field }
+
+// Cols returns the length of the rows.
+func (rr *RowReducer) Cols() int { return rr.cols }
+
+// Rank returns the rank of the rows added so far.
+func (rr *RowReducer) Rank() int { return len(rr.rows) }
+
+// IsFullRank returns true iff the rows added so far span every row of
+// length Cols().
+func (rr *RowReducer) IsFullRank() bool { return len(rr.rows) == rr.cols }
+
+// Pivots returns the pivot columns of the reduced rows, in increasing order.
+func (rr *RowReducer) Pivots() []int { return append([]int(nil), rr.pivots...) }
+
+// Add adds a row, which is copied, and returns true iff it is independent of
+// the rows added before it, increasing the rank.  It panics with
+// ErrDimensionMismatch if the row has the wrong length.
+func (rr *RowReducer) Add(row []byte) bool {
+	r := rr.reduce(row)
+	c := 0
+	for c < rr.cols && r[c] == 0 {
+		c++
+	}
+	if c == rr.cols {
+		return false
+	}
+	field := rr.field
+	if inv := field.Inv(r[c]); inv != 1 {
+		for j, v := range r[c:] {
+			r[c+j] = field.Mul(v, inv)
+		}
+	}
+	// Clear the new pivot column from the existing rows, then insert the
+	// new row in pivot order.
+	k := 0
+	for i, other := range rr.rows {
+		addScaled(field, other[c:], r[c:], other[c])
+		if rr.pivots[i] < c {
+			k = i + 1
+		}
+	}
+	rr.rows = append(rr.rows, nil)
+	copy(rr.rows[k+1:], rr.rows[k:])
+	rr.rows[k] = r
+	rr.pivots = append(rr.pivots, 0)
+	copy(rr.pivots[k+1:], rr.pivots[k:])
+	rr.pivots[k] = c
+	return true
+}
+
+// Contains returns true iff row lies in the span of the rows added so far.
+// It panics with ErrDimensionMismatch if the row has the wrong length.
+func (rr *RowReducer) Contains(row []byte) bool {
+	for _, v := range rr.reduce(row) {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Matrix returns the reduced row echelon form of the rows added so far, with
+// one row per unit of rank.
+func (rr *RowReducer) Matrix() Matrix {
+	m := NewMatrix(rr.field, len(rr.rows), rr.cols)
+	for i, row := range rr.rows {
+		copy(m.row(i), row)
+	}
+	return m
+}
+
+// reduce returns a copy of row with the pivot columns cleared.
+func (rr *RowReducer) reduce(row []byte) []byte {
+	if len(row) != rr.cols {
+		panic(ErrDimensionMismatch)
+	}
+	r := append([]byte(nil), row...)
+	for i, c := range rr.pivots {
+		addScaled(rr.field, r[c:], rr.rows[i][c:], r[c])
+	}
+	return r
+}
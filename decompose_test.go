@@ -0,0 +1,180 @@
+package galoisfield
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestMatrix_ReducedRowEchelon(t *testing.T) {
+	m := NewMatrix(nil, 3, 4, 0, 2, 4, 6, 0, 1, 2, 3, 1, 1, 1, 1)
+	e, pivots := m.ReducedRowEchelon()
+	if len(pivots) != 2 || pivots[0] != 0 || pivots[1] != 1 {
+		t.Errorf("expected pivots [0 1], got %v", pivots)
+	}
+	for r, c := range pivots {
+		for i := 0; i < e.Rows(); i++ {
+			var expected byte
+			if i == r {
+				expected = 1
+			}
+			if e.At(i, c) != expected {
+				t.Errorf("column %d of (%v) is not a unit vector", c, e)
+			}
+		}
+	}
+	if !e.RowEchelon().Equal(e) {
+		t.Errorf("(%v) is not in row echelon form", e)
+	}
+}
+
+func TestMatrix_Nullspace(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		for trial := 0; trial < 16; trial++ {
+			r, c := prng.Intn(6), prng.Intn(6)
+			// A product of thin matrices has deficient rank.
+			k := prng.Intn(4)
+			m := randomMatrix(prng, field, r, k).Mul(randomMatrix(prng, field, k, c))
+			rank := m.Rank()
+			n := m.Nullspace()
+			if n.Rows() != c || n.Cols() != c-rank {
+				t.Errorf("%v: nullspace of (%v) is %d×%d, expected %d×%d",
+					field, m, n.Rows(), n.Cols(), c, c-rank)
+				continue
+			}
+			if !m.Mul(n).Equal(NewMatrix(field, r, c-rank)) || n.Rank() != c-rank {
+				t.Errorf("%v: (%v) is not a nullspace basis of (%v)", field, n, m)
+			}
+			cs := m.ColumnSpace()
+			if cs.Cols() != rank || cs.Rank() != rank || m.augment(cs).Rank() != rank {
+				t.Errorf("%v: (%v) is not a column space basis of (%v)", field, cs, m)
+			}
+		}
+	}
+}
+
+func TestLU(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		for trial := 0; trial < 32; trial++ {
+			n := prng.Intn(8)
+			m := randomMatrix(prng, field, n, n)
+			f, err := m.LU()
+			if m.Determinant() == 0 {
+				if err != ErrSingular {
+					t.Errorf("%v: expected (%v) to be singular, got %v", field, m, err)
+				}
+				continue
+			}
+			if err != nil {
+				t.Errorf("%v: LU of (%v): %v", field, m, err)
+				continue
+			}
+			if !f.P().Mul(m).Equal(f.L().Mul(f.U())) {
+				t.Errorf("%v: P*A != L*U for (%v): P=(%v), L=(%v), U=(%v)",
+					field, m, f.P(), f.L(), f.U())
+			}
+			if f.Determinant() != m.Determinant() {
+				t.Errorf("%v: det (%v): expected %d, got %d", field, m, m.Determinant(), f.Determinant())
+			}
+			b := randomCoefficients(prng, int(field.Size()), n)
+			if x := f.Solve(b); !equalBytes(m.MulVec(x), b) {
+				t.Errorf("%v: (%v) * %v != %v", field, m, x, b)
+			}
+			bs := randomMatrix(prng, field, n, 3)
+			if x := f.SolveMatrix(bs); !m.Mul(x).Equal(bs) {
+				t.Errorf("%v: (%v) * (%v) != (%v)", field, m, x, bs)
+			}
+			if expected, _ := m.Inverse(); !f.Inverse().Equal(expected) {
+				t.Errorf("%v: inverse of (%v): expected (%v), got (%v)", field, m, expected, f.Inverse())
+			}
+		}
+	}
+}
+
+func TestRowReducer(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		for trial := 0; trial < 16; trial++ {
+			cols := 1 + prng.Intn(6)
+			rr := NewRowReducer(field, cols)
+			var rows [][]byte
+			for i := 0; i < 2*cols; i++ {
+				row := make([]byte, cols)
+				if prng.Intn(3) > 0 || len(rows) == 0 {
+					row = randomMatrix(prng, field, 1, cols).Row(0)
+				} else {
+					// A combination of earlier rows.
+					for _, prev := range rows {
+						addScaled(field, row, prev, byte(prng.Intn(int(field.Size()))))
+					}
+				}
+				rows = append(rows, row)
+				m := NewMatrixFromRows(field, rows...)
+				before := rr.Rank()
+				if independent := rr.Add(row); independent != (rr.Rank() > before) {
+					t.Errorf("%v: Add returned %v, but rank went from %d to %d", field, independent, before, rr.Rank())
+				}
+				if rr.Rank() != m.Rank() {
+					t.Errorf("%v: rank of (%v): expected %d, got %d", field, m, m.Rank(), rr.Rank())
+				}
+				if !rr.Contains(row) {
+					t.Errorf("%v: span of (%v) does not contain %v", field, rr.Matrix(), row)
+				}
+				e, pivots := m.ReducedRowEchelon()
+				if expected := e.SubMatrix(columnRange(0, len(pivots)), nil); !rr.Matrix().Equal(expected) {
+					t.Errorf("%v: expected (%v), got (%v)", field, expected, rr.Matrix())
+				}
+				if !equalInts(rr.Pivots(), pivots) {
+					t.Errorf("%v: expected pivots %v, got %v", field, pivots, rr.Pivots())
+				}
+			}
+			if rr.IsFullRank() != (rr.Rank() == cols) {
+				t.Errorf("%v: IsFullRank is wrong at rank %d", field, rr.Rank())
+			}
+		}
+	}
+}
+
+func TestDecompose_panics(t *testing.T) {
+	type testrow struct {
+		fn       func()
+		expected error
+	}
+	f, _ := Identity(nil, 2).LU()
+	for idx, row := range []testrow{
+		testrow{func() { NewMatrix(nil, 2, 3).LU() }, ErrDimensionMismatch},
+		testrow{func() { f.Solve([]byte{1}) }, ErrDimensionMismatch},
+		testrow{func() { f.SolveMatrix(NewMatrix(nil, 3, 1)) }, ErrDimensionMismatch},
+		testrow{func() { f.SolveMatrix(NewMatrix(Poly210_g2, 2, 1)) }, ErrIncompatibleFields},
+		testrow{func() { NewRowReducer(nil, 2).Add([]byte{1}) }, ErrDimensionMismatch},
+		testrow{func() { NewRowReducer(nil, -1) }, ErrDimensionMismatch},
+	} {
+		if e := panicValue(row.fn); e != row.expected {
+			t.Errorf("[%d] expected panic(%v), got %v", idx, row.expected, e)
+		}
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func BenchmarkLU_Solve(b *testing.B) {
+	prng := rand.New(rand.NewSource(42))
+	m := randomMatrix(prng, nil, 64, 64)
+	f, _ := m.LU()
+	v := randomCoefficients(prng, 256, 64)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		f.Solve(v)
+	}
+}
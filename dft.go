@@ -0,0 +1,109 @@
+package galoisfield
+
+// DFT returns the discrete Fourier transform of data over this field, with
+// respect to the generator g: the result has length n = Size()-1, and its
+// j'th entry is
+//
+//	∑_i data[i] * g**(i*j)
+//
+// That is, if data holds the coefficients of a polynomial a, then the
+// result holds a(g**0), a(g**1), ..., a(g**(n-1)), the values at every
+// non-zero element in the order given by Exp.  Since g**n = 1, data may be
+// longer than n; higher coefficients wrap around.
+//
+// The transform uses a mixed-radix Cooley-Tukey decomposition over the
+// prime factors of n (for GF(256), n = 255 = 3*5*17), taking O(n * ∑ p)
+// field operations instead of O(n**2).
+func (gf *GF) DFT(data []byte) []byte {
+	n := int(gf.m)
+	in := make([]byte, n)
+	for i, d := range data {
+		in[i%n] ^= d
+	}
+	out := make([]byte, n)
+	gf.dft(out, in, 1, 1, dftRadices(gf.m))
+	return out
+}
+
+// IDFT returns the inverse of DFT: given the values of a polynomial at
+// g**0, g**1, ..., g**(n-1), where n = Size()-1, it returns the coefficients
+// of the unique polynomial of degree less than n which takes those values.
+// It panics with ErrTransformSize if len(values) != n.
+//
+// The inverse transform is the forward transform with respect to g**-1,
+// divided by n.  Because n is odd, n = 1 in characteristic 2 and no division
+// is needed.
+func (gf *GF) IDFT(values []byte) []byte {
+	n := int(gf.m)
+	if len(values) != n {
+		panic(ErrTransformSize)
+	}
+	out := make([]byte, n)
+	gf.dft(out, values, 1, gf.m-1, dftRadices(gf.m))
+	return out
+}
+
+// dft computes the transform of in[0], in[stride], in[2*stride], ... into
+// out, with respect to the root of unity g**step.  The length of the
+// transform is len(out), which must be the product of radices.
+//
+// This is decimation in time: for the first radix p and n = p*m, the input
+// is split into p interleaved subsequences of length m, each transformed
+// recursively with root g**(step*p), and the results are combined with
+// one radix-p butterfly per output index modulo m.
+func (gf *GF) dft(out, in []byte, stride int, step uint, radices []uint) {
+	n := len(out)
+	if n == 1 {
+		out[0] = in[0]
+		return
+	}
+	p := int(radices[0])
+	m := n / p
+	for r := 0; r < p; r++ {
+		gf.dft(out[r*m:(r+1)*m], in[r*stride:], stride*p, (step*uint(p))%gf.m, radices[1:])
+	}
+
+	// out[k + s*m] = ∑_r w**(r*(k + s*m)) * sub_r[k], with w = g**step
+	// of order n.
+	sums := make([]byte, p)
+	for k := 0; k < m; k++ {
+		for s := range sums {
+			sums[s] = 0
+		}
+		for r := 0; r < p; r++ {
+			v := out[r*m+k]
+			if v == 0 {
+				continue
+			}
+			logv := uint(gf.log[v])
+			e := (step * uint(r*k)) % gf.m
+			de := (step * uint(r*m)) % gf.m
+			for s := 0; s < p; s++ {
+				sums[s] ^= gf.exp[logv+e]
+				e += de
+				if e >= gf.m {
+					e -= gf.m
+				}
+			}
+		}
+		for s, v := range sums {
+			out[k+s*m] = v
+		}
+	}
+}
+
+// dftRadices returns the prime factors of n with multiplicity, in ascending
+// order.
+func dftRadices(n uint) []uint {
+	var radices []uint
+	for p := uint(2); p*p <= n; p++ {
+		for n%p == 0 {
+			radices = append(radices, p)
+			n /= p
+		}
+	}
+	if n > 1 {
+		radices = append(radices, n)
+	}
+	return radices
+}
@@ -0,0 +1,92 @@
+package galoisfield
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestGF_DFT(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		n := int(field.Size()) - 1
+		for trial := 0; trial < 8; trial++ {
+			data := make([]byte, prng.Intn(2*n+1))
+			for i := range data {
+				data[i] = byte(prng.Intn(n + 1))
+			}
+			poly := NewPolynomial(field, data...)
+
+			values := field.DFT(data)
+			if len(values) != n {
+				t.Fatalf("%v: expected %d values, got %d", field, n, len(values))
+			}
+			for j, v := range values {
+				x := field.Exp(byte(j))
+				if expected := poly.Evaluate(x); v != expected {
+					t.Errorf("%v: DFT of (%v) at g**%d=%d: expected %d, got %d",
+						field, poly, j, x, expected, v)
+				}
+			}
+
+			coefficients := field.IDFT(values)
+			xs := make([]byte, n)
+			for j := range xs {
+				xs[j] = field.Exp(byte(j))
+			}
+			expected, _ := Interpolate(field, xs, values)
+			if actual := NewPolynomial(field, coefficients...); !actual.Equal(expected) {
+				t.Errorf("%v: expected IDFT to give (%v), got (%v)", field, expected, actual)
+			}
+		}
+	}
+}
+
+func TestGF_IDFT_size(t *testing.T) {
+	if e := panicValue(func() { Default.IDFT(make([]byte, 256)) }); e != ErrTransformSize {
+		t.Errorf("expected panic(ErrTransformSize), got %v", e)
+	}
+}
+
+func TestDFTRadices(t *testing.T) {
+	type testrow struct {
+		input    uint
+		expected []uint
+	}
+	for _, row := range []testrow{
+		testrow{3, []uint{3}},
+		testrow{15, []uint{3, 5}},
+		testrow{63, []uint{3, 3, 7}},
+		testrow{127, []uint{127}},
+		testrow{255, []uint{3, 5, 17}},
+	} {
+		actual := dftRadices(row.input)
+		if len(actual) != len(row.expected) {
+			t.Errorf("dftRadices(%d): expected %v, got %v", row.input, row.expected, actual)
+			continue
+		}
+		for i := range actual {
+			if actual[i] != row.expected[i] {
+				t.Errorf("dftRadices(%d): expected %v, got %v", row.input, row.expected, actual)
+				break
+			}
+		}
+	}
+}
+
+func BenchmarkGF_DFT_256(b *testing.B) {
+	data := benchmarkPolynomial(254).Coefficients()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = Default.DFT(data)
+	}
+}
+
+func BenchmarkGF_DFT_evaluate_256(b *testing.B) {
+	p := benchmarkPolynomial(254)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < 255; j++ {
+			_ = p.Evaluate(Default.Exp(byte(j)))
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package galoisfield
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// ExtensionField represents GF(q**d), the extension of degree d of a base
+// field GF(q), as the polynomials over the base field reduced modulo an
+// irreducible polynomial of degree d.  Composite fields such as GF((2**4)**2)
+// are the form used by hardware and bitsliced implementations, since their
+// arithmetic reduces to a few operations in the small base field.
+//
+// Elements are Polynomial values over the base field of degree less than d.
+// The methods that take elements expect them to be reduced; Element reduces
+// an arbitrary polynomial.
+type ExtensionField struct {
+	base    *GF
+	modulus Polynomial // monic and irreducible
+	bits    uint       // log2 of the size
+	order   *big.Int   // size of the multiplicative group
+}
+
+// Extend returns the extension of base obtained by adjoining a root of the
+// given modulus, which is made monic.  If base is nil, Default is used.  It
+// panics with ErrIncompatibleFields if the modulus is drawn from a different
+// field, or with ErrReduciblePoly if it is not irreducible.
+func Extend(base *GF, modulus Polynomial) *ExtensionField {
+	if base == nil {
+		base = Default
+	}
+	if modulus.field != base {
+		panic(ErrIncompatibleFields)
+	}
+	if !modulus.IsIrreducible() {
+		panic(ErrReduciblePoly)
+	}
+	bits := uint(base.k) * modulus.Degree()
+	order := new(big.Int).Lsh(big.NewInt(1), bits)
+	order.Sub(order, big.NewInt(1))
+	return &ExtensionField{base, modulus.Monic(), bits, order}
+}
+
+// Base returns the base field.
+func (ef *ExtensionField) Base() *GF { return ef.base }
+
+// Modulus returns the monic irreducible polynomial defining the extension.
+func (ef *ExtensionField) Modulus() Polynomial { return ef.modulus }
+
+// Degree returns the degree of the extension over its base field.
+func (ef *ExtensionField) Degree() uint { return ef.modulus.Degree() }
+
+// Size returns the order of the extension field, q**d.
+func (ef *ExtensionField) Size() *big.Int {
+	return new(big.Int).Add(ef.order, big.NewInt(1))
+}
+
+// String returns a human-readable representation of this field.
+func (ef *ExtensionField) String() string {
+	return fmt.Sprintf("%v[x]/(%v)", ef.base, ef.modulus)
+}
+
+// Element returns the element represented by the polynomial a, that is, a
+// reduced modulo the modulus.
+func (ef *ExtensionField) Element(a Polynomial) Polynomial {
+	return a.Mod(ef.modulus)
+}
+
+// Zero returns the additive identity.
+func (ef *ExtensionField) Zero() Polynomial { return Polynomial{ef.base, nil} }
+
+// One returns the multiplicative identity.
+func (ef *ExtensionField) One() Polynomial { return NewPolynomial(ef.base, 1) }
+
+// Add returns x+y == x-y.
+func (ef *ExtensionField) Add(x, y Polynomial) Polynomial { return x.Add(y) }
+
+// Mul returns x*y.
+func (ef *ExtensionField) Mul(x, y Polynomial) Polynomial {
+	return x.Mul(y).Mod(ef.modulus)
+}
+
+// Div returns x/y.  It panics with ErrDivByZero if y is zero.
+func (ef *ExtensionField) Div(x, y Polynomial) Polynomial {
+	return ef.Mul(x, ef.Inv(y))
+}
+
+// Inv returns the multiplicative inverse of x, by the extended Euclidean
+// algorithm.  It panics with ErrDivByZero if x is zero.
+func (ef *ExtensionField) Inv(x Polynomial) Polynomial {
+	if x.field != ef.base {
+		panic(ErrIncompatibleFields)
+	}
+	if x.IsZero() {
+		panic(ErrDivByZero)
+	}
+	// Invariant: t0*x = r0 and t1*x = r1, modulo the modulus.
+	r0, r1 := ef.modulus, x
+	t0, t1 := ef.Zero(), ef.One()
+	for r1.Degree() > 0 {
+		q, r := r0.DivMod(r1)
+		r0, r1 = r1, r
+		t0, t1 = t1, t0.Add(q.Mul(t1))
+	}
+	// The modulus is irreducible, so r1 is a non-zero constant.
+	return t1.Scale(ef.base.Inv(r1.Coefficient(0))).Mod(ef.modulus)
+}
+
+// Pow returns x**e.  By convention, x**0 = 1 even if x is zero.  It panics
+// with ErrNegativeExponent if e is negative.
+func (ef *ExtensionField) Pow(x Polynomial, e *big.Int) Polynomial {
+	return x.PowMod(e, ef.modulus)
+}
+
+// IsGenerator returns true iff x generates the multiplicative group of the
+// field, i.e. x has order q**d - 1.
+func (ef *ExtensionField) IsGenerator(x Polynomial) bool {
+	if x.IsZero() {
+		return false
+	}
+	one := ef.One()
+	var e big.Int
+	for _, p := range mersenneFactors(ef.bits) {
+		e.Quo(ef.order, p)
+		if ef.Pow(x, &e).Equal(one) {
+			return false
+		}
+	}
+	return true
+}
+
+// Generator returns the first generator of the multiplicative group, in the
+// order of the elements' packed representations (see FieldIsomorphism).  If
+// the modulus is primitive, this is x itself, unless the degree is 1.
+//
+// About one element in every O(log log q**d) is a generator, so the search
+// is short.
+func (ef *ExtensionField) Generator() Polynomial {
+	d := ef.Degree()
+	k := uint(ef.base.k)
+	for n := uint64(2); ; n++ {
+		coefficients := make([]byte, d)
+		for i := range coefficients {
+			if shift := k * uint(i); shift < 64 {
+				coefficients[i] = byte(n>>shift) & byte(ef.base.m)
+			}
+		}
+		if x := NewPolynomial(ef.base, coefficients...); ef.IsGenerator(x) {
+			return x
+		}
+	}
+}
+
+// FieldIsomorphism converts between the elements of an extension field of at
+// most 256 elements and those of a flat GF(2**k) of the same size.
+type FieldIsomorphism struct {
+	ext    *ExtensionField
+	flat   *GF
+	toFlat []byte // indexed by packed extension element
+	toExt  []byte // packed extension element, indexed by flat element
+}
+
+// Flatten returns an isomorphism between this extension field and the flat
+// field of the same size.  If flat is nil, the DefaultGF of that size is
+// used.  It panics with ErrFieldSize if the extension has more than 256
+// elements or flat is of a different size.
+//
+// The isomorphism maps a root α of flat's polynomial to a root of the same
+// polynomial in the extension, and extends linearly from the basis 1, α,
+// α**2, ....  It respects addition and multiplication; flat's generator is
+// irrelevant.
+func (ef *ExtensionField) Flatten(flat *GF) *FieldIsomorphism {
+	if ef.bits > 8 {
+		panic(ErrFieldSize)
+	}
+	if flat == nil {
+		flat = defaultFields[ef.bits]
+	}
+	if uint(flat.k) != ef.bits {
+		panic(ErrFieldSize)
+	}
+	size := 1 << ef.bits
+	iso := &FieldIsomorphism{ef, flat, make([]byte, size), make([]byte, size)}
+
+	// Find a root of flat's polynomial, whose coefficients are 0 and 1.
+	var root Polynomial
+	for n := 1; n < size; n++ {
+		y := iso.unpack(byte(n))
+		value, power := ef.Zero(), ef.One()
+		for i := uint(0); i <= ef.bits; i++ {
+			if flat.p&(1<<i) != 0 {
+				value = value.Add(power)
+			}
+			power = ef.Mul(power, y)
+		}
+		if value.IsZero() {
+			root = y
+			break
+		}
+	}
+
+	powers := make([]byte, ef.bits)
+	power := ef.One()
+	for i := range powers {
+		powers[i] = iso.pack(power)
+		power = ef.Mul(power, root)
+	}
+	for b := 0; b < size; b++ {
+		var packed byte
+		for i, p := range powers {
+			if b&(1<<uint(i)) != 0 {
+				packed ^= p
+			}
+		}
+		iso.toExt[b] = packed
+		iso.toFlat[packed] = byte(b)
+	}
+	return iso
+}
+
+// Extension returns the extension field.
+func (iso *FieldIsomorphism) Extension() *ExtensionField { return iso.ext }
+
+// Flat returns the flat field.
+func (iso *FieldIsomorphism) Flat() *GF { return iso.flat }
+
+// ToFlat maps an element of the extension field to the flat field.
+func (iso *FieldIsomorphism) ToFlat(x Polynomial) byte {
+	return iso.toFlat[iso.pack(x.Mod(iso.ext.modulus))]
+}
+
+// FromFlat maps an element of the flat field to the extension field.
+func (iso *FieldIsomorphism) FromFlat(b byte) Polynomial {
+	return iso.unpack(iso.toExt[b])
+}
+
+// pack returns the element with coefficient c_i at bits [k*i, k*(i+1)),
+// where k is the bit size of the base field.
+func (iso *FieldIsomorphism) pack(x Polynomial) byte {
+	var packed byte
+	for i, c := range x.coefficients {
+		packed |= c << (uint(iso.ext.base.k) * uint(i))
+	}
+	return packed
+}
+
+// unpack is the inverse of pack.
+func (iso *FieldIsomorphism) unpack(packed byte) Polynomial {
+	base := iso.ext.base
+	coefficients := make([]byte, iso.ext.Degree())
+	for i := range coefficients {
+		coefficients[i] = (packed >> (uint(base.k) * uint(i))) & byte(base.m)
+	}
+	return NewPolynomial(base, coefficients...)
+}
+
+// defaultFields holds the DefaultGF of each size, indexed by log2 of the
+// size.
+var defaultFields = []*GF{
+	2: DefaultGF4,
+	3: DefaultGF8,
+	4: DefaultGF16,
+	5: DefaultGF32,
+	6: DefaultGF64,
+	7: DefaultGF128,
+	8: DefaultGF256,
+}
@@ -0,0 +1,138 @@
+package galoisfield
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func TestExtensionField_small(t *testing.T) {
+	for _, row := range []struct {
+		base *GF
+		d    uint
+	}{
+		{Poly210_g2, 2},
+		{Poly210_g2, 3},
+		{Poly210_g2, 4},
+		{Poly410_g2, 2},
+		{Poly310_g2, 2},
+		{Poly410_g2, 1},
+	} {
+		ef := Extend(row.base, LowWeightIrreducible(row.base, row.d))
+		size := int(ef.Size().Int64())
+		if size != 1<<(uint(row.base.k)*row.d) {
+			t.Errorf("%v: expected size %d, got %d", ef, 1<<(uint(row.base.k)*row.d), size)
+		}
+		for _, flat := range []*GF{nil, defaultFields[uint(row.base.k)*row.d]} {
+			iso := ef.Flatten(flat)
+			if flat == nil && iso.Flat().Size() != uint(size) {
+				t.Fatalf("%v: expected a flat field of size %d, got %v", ef, size, iso.Flat())
+			}
+			seen := make(map[byte]bool)
+			for a := 0; a < size; a++ {
+				x := iso.FromFlat(byte(a))
+				if iso.ToFlat(x) != byte(a) {
+					t.Errorf("%v: FromFlat(%d) = (%v) maps back to %d", ef, a, x, iso.ToFlat(x))
+				}
+				seen[iso.pack(x)] = true
+				for b := 0; b < size; b++ {
+					y := iso.FromFlat(byte(b))
+					if iso.ToFlat(ef.Mul(x, y)) != iso.Flat().Mul(byte(a), byte(b)) ||
+						iso.ToFlat(ef.Add(x, y)) != byte(a^b) {
+						t.Errorf("%v: (%v), (%v) do not map to %d, %d", ef, x, y, a, b)
+					}
+				}
+			}
+			if len(seen) != size {
+				t.Errorf("%v: expected a bijection, got %d images", ef, len(seen))
+			}
+		}
+	}
+}
+
+func TestExtensionField_Inv(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, row := range []struct {
+		base *GF
+		d    uint
+	}{
+		{Poly410_g2, 2},
+		{Default, 3},
+		{Default, 16},
+	} {
+		ef := Extend(row.base, LowWeightIrreducible(row.base, row.d))
+		q := int(row.base.Size())
+		for trial := 0; trial < 64; trial++ {
+			x := ef.Element(NewPolynomial(row.base, randomCoefficients(prng, q, 2*int(row.d))...))
+			if x.IsZero() {
+				continue
+			}
+			if p := ef.Mul(x, ef.Inv(x)); !p.Equal(ef.One()) {
+				t.Errorf("%v: (%v) * (%v)**-1 = (%v)", ef, x, x, p)
+			}
+			y := ef.Element(NewPolynomial(row.base, randomCoefficients(prng, q, int(row.d))...))
+			if p := ef.Mul(ef.Div(y, x), x); !p.Equal(y) {
+				t.Errorf("%v: ((%v) / (%v)) * (%v) = (%v)", ef, y, x, x, p)
+			}
+			// Lagrange: x**(q**d - 1) = 1.
+			if p := ef.Pow(x, ef.order); !p.Equal(ef.One()) {
+				t.Errorf("%v: (%v)**(q**d-1) = (%v)", ef, x, p)
+			}
+		}
+	}
+}
+
+func TestExtensionField_Generator(t *testing.T) {
+	ef := Extend(Poly410_g2, LowWeightIrreducible(Poly410_g2, 2))
+	iso := ef.Flatten(nil)
+	var generators int
+	for a := 0; a < 256; a++ {
+		if ef.IsGenerator(iso.FromFlat(byte(a))) {
+			generators++
+		}
+	}
+	// φ(255) = 128.
+	if generators != 128 {
+		t.Errorf("%v: expected 128 generators, got %d", ef, generators)
+	}
+	g := ef.Generator()
+	seen := make(map[byte]bool)
+	x := ef.One()
+	for i := 0; i < 255; i++ {
+		seen[iso.ToFlat(x)] = true
+		x = ef.Mul(x, g)
+	}
+	if len(seen) != 255 {
+		t.Errorf("%v: expected (%v) to generate 255 elements, got %d", ef, g, len(seen))
+	}
+
+	// 3 divides 2**128 - 1, so the cube of a generator is not a generator.
+	large := Extend(Default, LowWeightIrreducible(Default, 16))
+	if g := large.Generator(); !large.IsGenerator(g) || large.IsGenerator(large.Pow(g, big.NewInt(3))) {
+		t.Errorf("%v: bad generator (%v)", large, g)
+	}
+}
+
+func TestExtensionField_panics(t *testing.T) {
+	type testrow struct {
+		fn       func()
+		expected error
+	}
+	ef := Extend(Poly410_g2, LowWeightIrreducible(Poly410_g2, 2))
+	for idx, row := range []testrow{
+		testrow{func() { Extend(nil, NewPolynomial(nil, 0, 0, 1)) }, ErrReduciblePoly},
+		testrow{func() { Extend(nil, NewPolynomial(nil, 5)) }, ErrReduciblePoly},
+		testrow{func() { Extend(nil, NewPolynomial(Poly410_g2, 1, 1)) }, ErrIncompatibleFields},
+		testrow{func() { ef.Inv(ef.Zero()) }, ErrDivByZero},
+		testrow{func() { ef.Inv(NewPolynomial(nil, 1)) }, ErrIncompatibleFields},
+		testrow{func() { ef.Flatten(DefaultGF16) }, ErrFieldSize},
+		testrow{func() { Extend(nil, LowWeightIrreducible(nil, 2)).Flatten(nil) }, ErrFieldSize},
+	} {
+		if e := panicValue(row.fn); e != row.expected {
+			t.Errorf("[%d] expected panic(%v), got %v", idx, row.expected, e)
+		}
+	}
+	if s := ef.Size(); s.Cmp(big.NewInt(256)) != 0 {
+		t.Errorf("expected size 256, got %v", s)
+	}
+}
@@ -0,0 +1,311 @@
+package galoisfield
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// Factor is an irreducible factor of a polynomial, together with its
+// multiplicity.
+type Factor struct {
+	Polynomial   Polynomial
+	Multiplicity uint
+}
+
+// Factor returns the factorization of this polynomial into monic irreducible
+// polynomials, in ascending order (see Compare).  The product of the factors,
+// each raised to its multiplicity, times LeadingCoefficient() is equal to the
+// original polynomial.  Constant polynomials have no factors.
+//
+// The factorization proceeds by square-free decomposition, then
+// distinct-degree factorization, and finally equal-degree splitting using
+// the Cantor-Zassenhaus algorithm.
+func (a Polynomial) Factor() []Factor {
+	return a.factor(func(f Polynomial) []Polynomial {
+		var result []Polynomial
+		for _, dd := range distinctDegreeFactor(f) {
+			result = append(result, equalDegreeFactor(dd.Polynomial, dd.Multiplicity)...)
+		}
+		return result
+	})
+}
+
+// FactorBerlekamp returns the same factorization as Factor, but splits each
+// square-free part using Berlekamp's algorithm instead of Cantor-Zassenhaus.
+// Berlekamp's algorithm is deterministic and does its work with linear
+// algebra over the coefficient field, which tends to be faster for
+// polynomials with many small factors over small fields.
+func (a Polynomial) FactorBerlekamp() []Factor {
+	return a.factor(berlekampFactor)
+}
+
+func (a Polynomial) factor(split func(Polynomial) []Polynomial) []Factor {
+	if a.Degree() == 0 {
+		return nil
+	}
+	var factors []Factor
+	for _, sf := range squareFreeFactor(a.Monic()) {
+		for _, p := range split(sf.Polynomial) {
+			factors = append(factors, Factor{p, sf.Multiplicity})
+		}
+	}
+	sort.Sort(byFactor(factors))
+	return factors
+}
+
+// IsSquareFree returns true iff this polynomial is not divisible by the
+// square of any non-constant polynomial.  The zero polynomial is not
+// square-free; non-zero constants are.
+func (a Polynomial) IsSquareFree() bool {
+	if a.IsZero() {
+		return false
+	}
+	return a.GCD(a.Derivative()).Degree() == 0
+}
+
+// squareFreeFactor decomposes the monic polynomial f into pairwise coprime
+// square-free monic polynomials f_i such that f = ∏ f_i**i.  The Multiplicity
+// field of each result holds i.
+//
+// In characteristic 2 the derivative of f may vanish even though f is not
+// constant; in that case f is a perfect square and its square root is
+// decomposed recursively.
+func squareFreeFactor(f Polynomial) []Factor {
+	field := f.field
+	one := NewPolynomial(field, 1)
+	var result []Factor
+
+	c := f.GCD(f.Derivative())
+	w, _ := f.DivMod(c)
+	for i := uint(1); !w.Equal(one); i++ {
+		y := w.GCD(c)
+		fac, _ := w.DivMod(y)
+		if fac.Degree() > 0 {
+			result = append(result, Factor{fac, i})
+		}
+		w = y
+		c, _ = c.DivMod(y)
+	}
+	if c.Degree() > 0 {
+		for _, sf := range squareFreeFactor(polySqrt(c)) {
+			result = append(result, Factor{sf.Polynomial, 2 * sf.Multiplicity})
+		}
+	}
+	return result
+}
+
+// polySqrt returns the square root of f, which must be a perfect square,
+// i.e. have only even-degree terms.
+func polySqrt(f Polynomial) Polynomial {
+	field := f.field
+	coefficients := make([]byte, (len(f.coefficients)+1)/2)
+	for i := range coefficients {
+		coefficients[i] = sqrt(field, f.coefficients[2*i])
+	}
+	return wrapPolynomial(field, coefficients)
+}
+
+// sqrt returns the unique y such that y*y == x.  Since the multiplicative
+// group has odd order m, squaring is inverted by raising to the (m+1)/2 power.
+func sqrt(field *GF, x byte) byte {
+	if x == 0 {
+		return 0
+	}
+	return field.exp[(uint(field.log[x])*((field.m+1)/2))%field.m]
+}
+
+// distinctDegreeFactor splits the square-free monic polynomial f into
+// products of irreducible factors which share the same degree.  The
+// Multiplicity field of each result holds that degree.
+func distinctDegreeFactor(f Polynomial) []Factor {
+	field := f.field
+	x := NewPolynomial(field, 0, 1)
+	var result []Factor
+	h := x
+	for i := uint(1); 2*i <= f.Degree(); i++ {
+		// h = x**(q**i) mod f
+		for j := byte(0); j < field.k; j++ {
+			h = wrapPolynomial(field, polySqrMod(field, h.coefficients, f.coefficients))
+		}
+		g := f.GCD(h.Add(x))
+		if g.Degree() > 0 {
+			result = append(result, Factor{g, i})
+			f, _ = f.DivMod(g)
+			h = h.Mod(f)
+		}
+	}
+	if f.Degree() > 0 {
+		result = append(result, Factor{f, f.Degree()})
+	}
+	return result
+}
+
+// equalDegreeFactor splits f, a product of distinct monic irreducible
+// polynomials of degree d, into its irreducible factors.
+//
+// This is the characteristic-2 variant of Cantor-Zassenhaus: for a random
+// polynomial r, the trace map T(r) = r + r**2 + ... + r**(2**(k*d-1)) takes
+// values in GF(2) modulo each irreducible factor, so gcd(f, T(r) mod f)
+// separates the factors where it is 0 from those where it is 1.  When d = 1,
+// the roots are found directly instead.
+func equalDegreeFactor(f Polynomial, d uint) []Polynomial {
+	field := f.field
+	n := f.Degree()
+	if n == d {
+		return []Polynomial{f}
+	}
+	if d == 1 {
+		var result []Polynomial
+		for _, r := range findRoots(field, f.coefficients) {
+			result = append(result, NewPolynomial(field, r, 1))
+		}
+		return result
+	}
+	prng := rand.New(rand.NewSource(int64(n)))
+	for {
+		r := make([]byte, n)
+		for i := range r {
+			r[i] = byte(prng.Intn(int(field.Size())))
+		}
+		t := reduce(r)
+		if len(t) == 0 {
+			continue
+		}
+		y := t
+		sum := expand(int(n), t)
+		for i := uint(1); i < uint(field.k)*d; i++ {
+			y = polySqrMod(field, y, f.coefficients)
+			for j, yj := range y {
+				sum[j] ^= yj
+			}
+		}
+		g := f.GCD(wrapPolynomial(field, sum))
+		if g.Degree() == 0 || g.Degree() == n {
+			continue
+		}
+		h, _ := f.DivMod(g)
+		return append(equalDegreeFactor(g, d), equalDegreeFactor(h, d)...)
+	}
+}
+
+// berlekampFactor splits the square-free monic polynomial f into its
+// irreducible factors using Berlekamp's algorithm.
+//
+// The Berlekamp subalgebra {v : v**q ≡ v (mod f)} is the nullspace of Q - I,
+// where row i of Q holds the coefficients of x**(q*i) mod f.  Its dimension is
+// the number of irreducible factors of f, and for any v in it,
+// f = ∏_{s ∈ GF(q)} gcd(f, v - s).
+func berlekampFactor(f Polynomial) []Polynomial {
+	field := f.field
+	n := int(f.Degree())
+	if n <= 1 {
+		return []Polynomial{f}
+	}
+
+	// Build Q - I.
+	xq := wrapPolynomial(field, polyXPow2Mod(field, uint(field.k), f.coefficients))
+	row := NewPolynomial(field, 1)
+	matrix := make([][]byte, n)
+	for i := range matrix {
+		matrix[i] = expand(n, row.coefficients)
+		matrix[i][i] ^= 1
+		row = row.Mul(xq).Mod(f)
+	}
+	basis := leftNullspace(field, matrix)
+	if len(basis) == 1 {
+		return []Polynomial{f}
+	}
+
+	factors := []Polynomial{f}
+	for _, vcoeff := range basis {
+		v := wrapPolynomial(field, vcoeff)
+		if v.Degree() == 0 {
+			continue
+		}
+		var next []Polynomial
+		for _, u := range factors {
+			if u.Degree() == 1 {
+				next = append(next, u)
+				continue
+			}
+			for s := uint(0); s < field.Size() && u.Degree() > 0; s++ {
+				g := u.GCD(v.Add(NewPolynomial(field, byte(s))))
+				if g.Degree() > 0 {
+					next = append(next, g)
+					u, _ = u.DivMod(g)
+				}
+			}
+		}
+		factors = next
+		if len(factors) == len(basis) {
+			break
+		}
+	}
+	return factors
+}
+
+// leftNullspace returns a basis for {v : v*M = 0}, where M is the given square
+// matrix.  The matrix is destroyed.
+func leftNullspace(field *GF, matrix [][]byte) [][]byte {
+	n := len(matrix)
+	// Column-reduce M by row-reducing its transpose.
+	t := make([][]byte, n)
+	for i := range t {
+		t[i] = make([]byte, n)
+		for j := range t[i] {
+			t[i][j] = matrix[j][i]
+		}
+	}
+	var pivots []int
+	isPivot := make([]bool, n)
+	r := 0
+	for c := 0; c < n && r < n; c++ {
+		p := r
+		for p < n && t[p][c] == 0 {
+			p++
+		}
+		if p == n {
+			continue
+		}
+		t[r], t[p] = t[p], t[r]
+		inv := field.Inv(t[r][c])
+		for j := range t[r] {
+			t[r][j] = field.Mul(t[r][j], inv)
+		}
+		for i := range t {
+			if i != r && t[i][c] != 0 {
+				s := t[i][c]
+				for j := range t[i] {
+					t[i][j] ^= field.Mul(s, t[r][j])
+				}
+			}
+		}
+		pivots = append(pivots, c)
+		isPivot[c] = true
+		r++
+	}
+	var basis [][]byte
+	for free := 0; free < n; free++ {
+		if isPivot[free] {
+			continue
+		}
+		v := make([]byte, n)
+		v[free] = 1
+		for i, c := range pivots {
+			v[c] = t[i][free]
+		}
+		basis = append(basis, v)
+	}
+	return basis
+}
+
+type byFactor []Factor
+
+func (s byFactor) Len() int { return len(s) }
+func (s byFactor) Less(i, j int) bool {
+	if cmp := s[i].Polynomial.Compare(s[j].Polynomial); cmp != 0 {
+		return cmp < 0
+	}
+	return s[i].Multiplicity < s[j].Multiplicity
+}
+func (s byFactor) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
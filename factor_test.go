@@ -0,0 +1,187 @@
+package galoisfield
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestPolynomial_Factor(t *testing.T) {
+	type testrow struct {
+		input    Polynomial
+		expected []Factor
+	}
+	for idx, row := range []testrow{
+		testrow{NewPolynomial(nil), nil},
+		testrow{NewPolynomial(nil, 3), nil},
+		testrow{NewPolynomial(nil, 0, 3),
+			[]Factor{
+				Factor{NewPolynomial(nil, 0, 1), 1},
+			}},
+		testrow{NewPolynomial(nil, 1, 0, 1),
+			[]Factor{
+				Factor{NewPolynomial(nil, 1, 1), 2},
+			}},
+		testrow{NewPolynomial(Poly210_g2, 0, 1, 0, 0, 1),
+			[]Factor{
+				Factor{NewPolynomial(Poly210_g2, 0, 1), 1},
+				Factor{NewPolynomial(Poly210_g2, 1, 1), 1},
+				Factor{NewPolynomial(Poly210_g2, 2, 1), 1},
+				Factor{NewPolynomial(Poly210_g2, 3, 1), 1},
+			}},
+		testrow{NewPolynomial(Poly210_g2, 2, 1, 1).
+			Mul(NewPolynomial(Poly210_g2, 2, 1, 1)).
+			Mul(NewPolynomial(Poly210_g2, 0, 0, 0, 2)),
+			[]Factor{
+				Factor{NewPolynomial(Poly210_g2, 0, 1), 3},
+				Factor{NewPolynomial(Poly210_g2, 2, 1, 1), 2},
+			}},
+	} {
+		for _, impl := range []struct {
+			name string
+			fn   func(Polynomial) []Factor
+		}{
+			{"Factor", Polynomial.Factor},
+			{"FactorBerlekamp", Polynomial.FactorBerlekamp},
+		} {
+			actual := impl.fn(row.input)
+			if !equalFactors(actual, row.expected) {
+				t.Errorf("[%2d] %s: expected factors of (%v) to be %v, got %v",
+					idx, impl.name, row.input, row.expected, actual)
+			}
+		}
+	}
+}
+
+func TestPolynomial_Factor_random(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		n := int(field.Size())
+		for trial := 0; trial < 32; trial++ {
+			var p []byte
+			for i := 1 + prng.Intn(12); i >= 0; i-- {
+				p = append(p, byte(prng.Intn(n)))
+			}
+			p = append(p, byte(1+prng.Intn(n-1)))
+			poly := NewPolynomial(field, p...)
+			if trial%4 == 0 {
+				poly = poly.Mul(poly)
+			}
+
+			cz := poly.Factor()
+			checkFactorization(t, "Factor", poly, cz)
+			bk := poly.FactorBerlekamp()
+			checkFactorization(t, "FactorBerlekamp", poly, bk)
+			if !equalFactors(cz, bk) {
+				t.Errorf("expected Factor and FactorBerlekamp to agree for (%v), got %v vs %v",
+					poly, cz, bk)
+			}
+		}
+	}
+}
+
+func TestPolynomial_IsIrreducible(t *testing.T) {
+	// The number of monic irreducible polynomials of degree n over GF(q)
+	// is (1/n) ∑_{d|n} μ(d) q**(n/d).
+	type testrow struct {
+		field    *GF
+		degree   uint
+		expected int
+	}
+	for _, row := range []testrow{
+		testrow{Poly210_g2, 1, 4},
+		testrow{Poly210_g2, 2, 6},
+		testrow{Poly210_g2, 3, 20},
+		testrow{Poly210_g2, 4, 60},
+		testrow{Poly310_g2, 2, 28},
+		testrow{Poly310_g2, 3, 168},
+		testrow{Poly410_g2, 2, 120},
+	} {
+		q := row.field.Size()
+		total := uint(1)
+		for i := uint(0); i < row.degree; i++ {
+			total *= q
+		}
+		count := 0
+		for i := uint(0); i < total; i++ {
+			coefficients := make([]byte, row.degree+1)
+			for j, v := uint(0), i; j < row.degree; j, v = j+1, v/q {
+				coefficients[j] = byte(v % q)
+			}
+			coefficients[row.degree] = 1
+			p := NewPolynomial(row.field, coefficients...)
+			irreducible := p.IsIrreducible()
+			if irreducible {
+				count++
+			}
+			factors := p.Factor()
+			expect := len(factors) == 1 && factors[0].Multiplicity == 1
+			if irreducible != expect {
+				t.Errorf("IsIrreducible(%v) over %v: expected %v, got %v",
+					p, row.field, expect, irreducible)
+			}
+		}
+		if count != row.expected {
+			t.Errorf("expected %d monic irreducibles of degree %d over %v, got %d",
+				row.expected, row.degree, row.field, count)
+		}
+	}
+}
+
+func TestPolynomial_IsSquareFree(t *testing.T) {
+	type testrow struct {
+		input    Polynomial
+		expected bool
+	}
+	for _, row := range []testrow{
+		testrow{NewPolynomial(nil), false},
+		testrow{NewPolynomial(nil, 1), true},
+		testrow{NewPolynomial(nil, 0, 1), true},
+		testrow{NewPolynomial(nil, 0, 0, 1), false},
+		testrow{NewPolynomial(nil, 1, 0, 1), false},
+		testrow{NewPolynomial(nil, 6, 1, 1), true},
+		testrow{NewPolynomial(nil, 6, 1, 1).Mul(NewPolynomial(nil, 2, 1)), false},
+	} {
+		actual := row.input.IsSquareFree()
+		if actual != row.expected {
+			t.Errorf("IsSquareFree(%v): expected %v, got %v",
+				row.input, row.expected, actual)
+		}
+	}
+}
+
+func checkFactorization(t *testing.T, name string, p Polynomial, factors []Factor) {
+	product := NewPolynomial(p.Field(), p.LeadingCoefficient())
+	for i, f := range factors {
+		if f.Polynomial.LeadingCoefficient() != 1 {
+			t.Errorf("%s: expected monic factors of (%v), got (%v)",
+				name, p, f.Polynomial)
+		}
+		if !f.Polynomial.IsIrreducible() {
+			t.Errorf("%s: expected irreducible factors of (%v), got (%v)",
+				name, p, f.Polynomial)
+		}
+		if i > 0 && !factors[i-1].Polynomial.Less(f.Polynomial) {
+			t.Errorf("%s: expected sorted, distinct factors of (%v), got %v",
+				name, p, factors)
+		}
+		for j := uint(0); j < f.Multiplicity; j++ {
+			product = product.Mul(f.Polynomial)
+		}
+	}
+	if !product.Equal(p) {
+		t.Errorf("%s: expected factors of (%v) to multiply back, got %v = (%v)",
+			name, p, factors, product)
+	}
+}
+
+func equalFactors(a, b []Factor) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Polynomial.Equal(b[i].Polynomial) || a[i].Multiplicity != b[i].Multiplicity {
+			return false
+		}
+	}
+	return true
+}
@@ -313,16 +313,48 @@ func mulSlow(x, y, poly, k byte) byte {
 	return p
 }
 
-// isReducible returns true iff it can find a smaller polynomial that evenly
-// divides the given polynomial.
+// isReducible returns true iff the binary polynomial p can be factored over
+// GF(2).  It uses Rabin's test, the same as Polynomial.IsIrreducible, but
+// works directly on polynomials packed into a uint.
 func isReducible(p uint) bool {
-	var n uint = 1 << ((degree(p) / 2) + 1)
-	for divisor := uint(2); divisor < n; divisor++ {
-		if polyDiv(p, divisor) == 0 {
+	n := degree(p) - 1
+	for _, r := range primeFactors(uint64(n)) {
+		if polyGCD2(polyXPow2Mod2(n/uint(r), p)^2, p) != 1 {
 			return true
 		}
 	}
-	return false
+	return polyXPow2Mod2(n, p) != 2
+}
+
+// polyXPow2Mod2 returns x**(2**i) mod p, where p is a binary polynomial
+// packed into a uint.
+func polyXPow2Mod2(i, p uint) uint {
+	x := polyDiv(2, p)
+	for ; i > 0; i-- {
+		x = polyDiv(polyMul2(x, x), p)
+	}
+	return x
+}
+
+// polyMul2 returns the product of two binary polynomials packed into uints.
+func polyMul2(a, b uint) uint {
+	var prod uint
+	for b != 0 {
+		if (b & 1) != 0 {
+			prod ^= a
+		}
+		a <<= 1
+		b >>= 1
+	}
+	return prod
+}
+
+// polyGCD2 returns the GCD of two binary polynomials packed into uints.
+func polyGCD2(a, b uint) uint {
+	for b != 0 {
+		a, b = b, polyDiv(a, b)
+	}
+	return a
 }
 
 // polyDiv divides two polynomials and returns the remainder.
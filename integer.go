@@ -0,0 +1,206 @@
+package galoisfield
+
+import (
+	"math/big"
+	"sync"
+)
+
+// primeFactors returns the distinct prime factors of n in ascending order.
+// It uses trial division, so n should be small (e.g. a polynomial degree).
+func primeFactors(n uint64) []uint64 {
+	var result []uint64
+	for p := uint64(2); p*p <= n; p++ {
+		if n%p == 0 {
+			result = append(result, p)
+			for n%p == 0 {
+				n /= p
+			}
+		}
+	}
+	if n > 1 {
+		result = append(result, n)
+	}
+	return result
+}
+
+var (
+	mersenneMu    sync.Mutex
+	mersenneCache = make(map[uint][]*big.Int)
+)
+
+// mersenneFactors returns the distinct prime factors of 2**m - 1 in ascending
+// order.  This is the order of the multiplicative group of GF(2**m), which
+// must be factored to test whether an element or polynomial is primitive.
+//
+// The factorization uses the algebraic identity 2**m - 1 = ∏_{d|m} Φ_d(2),
+// where Φ_d is the d'th cyclotomic polynomial, so that only the much smaller
+// cyclotomic values need to be factored numerically.  Results are cached.
+func mersenneFactors(m uint) []*big.Int {
+	mersenneMu.Lock()
+	cached, found := mersenneCache[m]
+	mersenneMu.Unlock()
+	if found {
+		return cached
+	}
+
+	seen := make(map[string]*big.Int)
+	for d := uint(1); d <= m; d++ {
+		if m%d != 0 {
+			continue
+		}
+		for _, p := range factorBig(cyclotomicAt2(d)) {
+			seen[p.String()] = p
+		}
+	}
+	result := make([]*big.Int, 0, len(seen))
+	for _, p := range seen {
+		result = append(result, p)
+	}
+	for i := 1; i < len(result); i++ {
+		for j := i; j > 0 && result[j].Cmp(result[j-1]) < 0; j-- {
+			result[j], result[j-1] = result[j-1], result[j]
+		}
+	}
+
+	mersenneMu.Lock()
+	mersenneCache[m] = result
+	mersenneMu.Unlock()
+	return result
+}
+
+// cyclotomicAt2 returns Φ_d(2) = ∏_{e|d} (2**e - 1)**μ(d/e).
+func cyclotomicAt2(d uint) *big.Int {
+	num := big.NewInt(1)
+	den := big.NewInt(1)
+	one := big.NewInt(1)
+	for e := uint(1); e <= d; e++ {
+		if d%e != 0 {
+			continue
+		}
+		v := new(big.Int).Lsh(one, e)
+		v.Sub(v, one)
+		switch moebius(uint64(d / e)) {
+		case 1:
+			num.Mul(num, v)
+		case -1:
+			den.Mul(den, v)
+		}
+	}
+	return num.Quo(num, den)
+}
+
+// moebius returns the Möbius function μ(n).
+func moebius(n uint64) int {
+	mu := 1
+	for p := uint64(2); p*p <= n; p++ {
+		if n%p == 0 {
+			n /= p
+			if n%p == 0 {
+				return 0
+			}
+			mu = -mu
+		}
+	}
+	if n > 1 {
+		mu = -mu
+	}
+	return mu
+}
+
+// factorBig returns the distinct prime factors of n, in no particular order,
+// using trial division by small primes followed by Pollard's rho method.
+func factorBig(n *big.Int) []*big.Int {
+	n = new(big.Int).Set(n)
+	var result []*big.Int
+	var q, r big.Int
+	for p := int64(2); p < 1000; p++ {
+		bp := big.NewInt(p)
+		if n.Cmp(bp) < 0 {
+			break
+		}
+		q.QuoRem(n, bp, &r)
+		if r.Sign() != 0 {
+			continue
+		}
+		result = append(result, bp)
+		for r.Sign() == 0 {
+			n.Set(&q)
+			q.QuoRem(n, bp, &r)
+		}
+	}
+	return append(result, factorBigRho(n)...)
+}
+
+func factorBigRho(n *big.Int) []*big.Int {
+	if n.Cmp(big.NewInt(1)) <= 0 {
+		return nil
+	}
+	if n.ProbablyPrime(32) {
+		return []*big.Int{n}
+	}
+	d := pollardRho(n)
+	e := new(big.Int).Quo(n, d)
+	result := factorBigRho(d)
+	for _, p := range factorBigRho(e) {
+		dup := false
+		for _, existing := range result {
+			if existing.Cmp(p) == 0 {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// pollardRho returns a non-trivial factor of the composite n, using Brent's
+// variant of Pollard's rho method.
+func pollardRho(n *big.Int) *big.Int {
+	one := big.NewInt(1)
+	for c := int64(1); ; c++ {
+		bc := big.NewInt(c)
+		step := func(x *big.Int) {
+			x.Mul(x, x)
+			x.Add(x, bc)
+			x.Mod(x, n)
+		}
+		x, y, ys := big.NewInt(2), big.NewInt(2), new(big.Int)
+		q, g, diff := big.NewInt(1), big.NewInt(1), new(big.Int)
+		const m = 128
+		for r := 1; g.Cmp(one) == 0; r *= 2 {
+			x.Set(y)
+			for i := 0; i < r; i++ {
+				step(y)
+			}
+			for k := 0; k < r && g.Cmp(one) == 0; k += m {
+				ys.Set(y)
+				for i := 0; i < m && i < r-k; i++ {
+					step(y)
+					diff.Sub(x, y)
+					diff.Abs(diff)
+					q.Mul(q, diff)
+					q.Mod(q, n)
+				}
+				g.GCD(nil, nil, q, n)
+			}
+		}
+		if g.Cmp(n) == 0 {
+			// Backtrack one step at a time from the last checkpoint.
+			for {
+				step(ys)
+				diff.Sub(x, ys)
+				diff.Abs(diff)
+				g.GCD(nil, nil, diff, n)
+				if g.Cmp(one) != 0 {
+					break
+				}
+			}
+		}
+		if g.Cmp(n) != 0 {
+			return g
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package galoisfield
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestPrimeFactors(t *testing.T) {
+	type testrow struct {
+		n        uint64
+		expected []uint64
+	}
+	for _, row := range []testrow{
+		testrow{1, nil},
+		testrow{2, []uint64{2}},
+		testrow{12, []uint64{2, 3}},
+		testrow{255, []uint64{3, 5, 17}},
+		testrow{997, []uint64{997}},
+		testrow{1 << 20, []uint64{2}},
+	} {
+		actual := primeFactors(row.n)
+		if len(actual) != len(row.expected) {
+			t.Errorf("primeFactors(%d): expected %v, got %v", row.n, row.expected, actual)
+			continue
+		}
+		for i := range actual {
+			if actual[i] != row.expected[i] {
+				t.Errorf("primeFactors(%d): expected %v, got %v", row.n, row.expected, actual)
+				break
+			}
+		}
+	}
+}
+
+func TestMersenneFactors(t *testing.T) {
+	type testrow struct {
+		m        uint
+		expected string
+	}
+	for _, row := range []testrow{
+		testrow{1, "[]"},
+		testrow{2, "[3]"},
+		testrow{8, "[3 5 17]"},
+		testrow{11, "[23 89]"},
+		testrow{16, "[3 5 17 257]"},
+		testrow{64, "[3 5 17 257 641 65537 6700417]"},
+		testrow{67, "[193707721 761838257287]"},
+	} {
+		actual := mersenneFactors(row.m)
+		if str := bigString(actual); str != row.expected {
+			t.Errorf("mersenneFactors(%d): expected %s, got %s", row.m, row.expected, str)
+		}
+	}
+
+	// Every result must be a prime that divides 2**m - 1, and together
+	// they must account for all of it.
+	for m := uint(1); m <= 100; m++ {
+		n := new(big.Int).Lsh(big.NewInt(1), m)
+		n.Sub(n, big.NewInt(1))
+		var q, r big.Int
+		for _, p := range mersenneFactors(m) {
+			if !p.ProbablyPrime(32) {
+				t.Errorf("mersenneFactors(%d): %v is not prime", m, p)
+			}
+			q.QuoRem(n, p, &r)
+			if r.Sign() != 0 {
+				t.Errorf("mersenneFactors(%d): %v does not divide 2**m - 1", m, p)
+				continue
+			}
+			for r.Sign() == 0 {
+				n.Set(&q)
+				q.QuoRem(n, p, &r)
+			}
+		}
+		if n.Cmp(big.NewInt(1)) != 0 {
+			t.Errorf("mersenneFactors(%d): unaccounted cofactor %v", m, n)
+		}
+	}
+}
+
+func bigString(list []*big.Int) string {
+	s := "["
+	for i, x := range list {
+		if i > 0 {
+			s += " "
+		}
+		s += x.String()
+	}
+	return s + "]"
+}
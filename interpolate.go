@@ -0,0 +1,191 @@
+package galoisfield
+
+import (
+	"errors"
+)
+
+var (
+	ErrLengthMismatch  = errors.New("number of x values does not match number of y values")
+	ErrDuplicatePoint  = errors.New("x values must be distinct")
+	ErrPointOutOfRange = errors.New("value is not an element of the field")
+)
+
+// interpolateTreeMin is the number of points at which Interpolate switches
+// from Lagrange's formula to the subproduct tree (see BenchmarkInterpolate_*).
+const interpolateTreeMin = 48
+
+// Interpolate returns the unique polynomial of degree less than len(xs) which
+// takes the value ys[i] at xs[i] for every i.  It returns ErrLengthMismatch if
+// xs and ys have different lengths, ErrDuplicatePoint if any x value appears
+// more than once, or ErrPointOutOfRange if any x or y value is not an element
+// of the field.  If field is nil, Default is used.
+//
+// This is Lagrange interpolation.  With w(x) = ∏_j (x - xs[j]), the result is
+//
+//	∑_i ys[i] * (w(x) / (x - xs[i])) / w'(xs[i])
+//
+// which takes O(n**2) field operations.  For more than a few dozen points,
+// the weights and the sum are instead computed with a subproduct tree, which
+// is asymptotically faster.
+func Interpolate(field *GF, xs, ys []byte) (Polynomial, error) {
+	if field == nil {
+		field = Default
+	}
+	if len(xs) != len(ys) {
+		return Polynomial{field, nil}, ErrLengthMismatch
+	}
+	if err := checkDistinct(field, xs); err != nil {
+		return Polynomial{field, nil}, err
+	}
+	for _, y := range ys {
+		if uint(y) >= field.Size() {
+			return Polynomial{field, nil}, ErrPointOutOfRange
+		}
+	}
+	n := len(xs)
+	if n == 0 {
+		return Polynomial{field, nil}, nil
+	}
+	if n >= interpolateTreeMin {
+		return wrapPolynomial(field, interpolateTree(field, xs, ys)), nil
+	}
+	return wrapPolynomial(field, interpolateLagrange(field, xs, ys)), nil
+}
+
+// interpolateLagrange computes the Lagrange formula directly.  The points
+// must be distinct.
+func interpolateLagrange(field *GF, xs, ys []byte) []byte {
+	n := len(xs)
+
+	// w(x) = ∏_j (x - xs[j]), which has degree n.
+	w := make([]byte, n+1)
+	w[0] = 1
+	for j, xj := range xs {
+		for i := j + 1; i > 0; i-- {
+			w[i] = w[i-1] ^ field.Mul(w[i], xj)
+		}
+		w[0] = field.Mul(w[0], xj)
+	}
+
+	sum := make([]byte, n)
+	q := make([]byte, n)
+	for i, xi := range xs {
+		if ys[i] == 0 {
+			continue
+		}
+		// Synthetic division: q(x) = w(x) / (x - xi), evaluated at xi
+		// along the way to give w'(xi).
+		var carry, denom byte
+		for d := n; d > 0; d-- {
+			carry = w[d] ^ field.Mul(carry, xi)
+			q[d-1] = carry
+			denom = field.Mul(denom, xi) ^ carry
+		}
+		s := field.Div(ys[i], denom)
+		for d, qd := range q {
+			sum[d] ^= field.Mul(s, qd)
+		}
+	}
+	return sum
+}
+
+// checkDistinct returns ErrDuplicatePoint if any value appears more than once
+// in xs, or ErrPointOutOfRange if any value is not less than field.Size().
+func checkDistinct(field *GF, xs []byte) error {
+	seen := make([]bool, field.Size())
+	for _, x := range xs {
+		if uint(x) >= field.Size() {
+			return ErrPointOutOfRange
+		}
+		if seen[x] {
+			return ErrDuplicatePoint
+		}
+		seen[x] = true
+	}
+	return nil
+}
+
+// NewtonInterpolator builds an interpolating polynomial one point at a time,
+// using Newton's divided differences.  Each call to Add takes O(n) field
+// operations, where n is the number of points added so far, and the current
+// interpolating polynomial is always available.
+//
+// The zero value is not usable; create one with NewNewtonInterpolator.
+type NewtonInterpolator struct {
+	field *GF
+	xs    []byte
+
+	// newton holds the Newton-form coefficients c_i of
+	// p(x) = ∑_i c_i * ∏_{j<i} (x - xs[j]).
+	newton []byte
+
+	// coefficients holds p in the standard basis, and basis holds
+	// ∏_j (x - xs[j]), so that adding a point is a single scaled addition.
+	coefficients []byte
+	basis        []byte
+}
+
+// NewNewtonInterpolator returns an interpolator with no points.  If field is
+// nil, Default is used.
+func NewNewtonInterpolator(field *GF) *NewtonInterpolator {
+	if field == nil {
+		field = Default
+	}
+	return &NewtonInterpolator{field: field, basis: []byte{1}}
+}
+
+// Field returns the Galois field over which this interpolator works.
+func (ni *NewtonInterpolator) Field() *GF { return ni.field }
+
+// Len returns the number of points added so far.
+func (ni *NewtonInterpolator) Len() int { return len(ni.xs) }
+
+// Add adds the point (x, y), so that the interpolating polynomial takes the
+// value y at x.  It returns ErrDuplicatePoint, and leaves the interpolator
+// unchanged, if x has already been added, or ErrPointOutOfRange if x or y is
+// not an element of the field.
+func (ni *NewtonInterpolator) Add(x, y byte) error {
+	field := ni.field
+	if uint(x) >= field.Size() || uint(y) >= field.Size() {
+		return ErrPointOutOfRange
+	}
+	// The new Newton coefficient is the divided difference
+	// (y - p(x)) / ∏_j (x - xs[j]).
+	value := ni.Evaluate(x)
+	var prod byte = 1
+	for _, xj := range ni.xs {
+		if xj == x {
+			return ErrDuplicatePoint
+		}
+		prod = field.Mul(prod, x^xj)
+	}
+	c := field.Div(y^value, prod)
+
+	ni.newton = append(ni.newton, c)
+	ni.coefficients = append(ni.coefficients, 0)
+	for i, bi := range ni.basis {
+		ni.coefficients[i] ^= field.Mul(c, bi)
+	}
+	ni.basis = append(ni.basis, 0)
+	for i := len(ni.basis) - 1; i > 0; i-- {
+		ni.basis[i] = ni.basis[i-1] ^ field.Mul(ni.basis[i], x)
+	}
+	ni.basis[0] = field.Mul(ni.basis[0], x)
+	ni.xs = append(ni.xs, x)
+	return nil
+}
+
+// Polynomial returns the unique polynomial of degree less than Len() which
+// passes through every point added so far.
+func (ni *NewtonInterpolator) Polynomial() Polynomial {
+	return NewPolynomial(ni.field, ni.coefficients...)
+}
+
+// Evaluate returns the value of the current interpolating polynomial at x.
+func (ni *NewtonInterpolator) Evaluate(x byte) byte {
+	var value byte
+	for i := len(ni.xs) - 1; i >= 0; i-- {
+		value = ni.field.Mul(value, x^ni.xs[i]) ^ ni.newton[i]
+	}
+	return value
+}
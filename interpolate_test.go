@@ -0,0 +1,116 @@
+package galoisfield
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestInterpolate(t *testing.T) {
+	type testrow struct {
+		field    *GF
+		xs, ys   []byte
+		expected Polynomial
+		err      error
+	}
+	for idx, row := range []testrow{
+		testrow{nil, nil, nil, NewPolynomial(nil), nil},
+		testrow{nil, []byte{5}, []byte{7}, NewPolynomial(nil, 7), nil},
+		testrow{nil, []byte{1, 2}, []byte{0, 0}, NewPolynomial(nil), nil},
+		testrow{nil, []byte{0, 1}, []byte{1, 0}, NewPolynomial(nil, 1, 1), nil},
+		testrow{nil, []byte{0, 1, 2}, []byte{0, 1, 4}, NewPolynomial(nil, 0, 0, 1), nil},
+		testrow{Poly210_g2, []byte{0, 1, 2, 3}, []byte{2, 2, 3, 3},
+			NewPolynomial(Poly210_g2, 2, 1, 1), nil},
+		testrow{nil, []byte{1, 2}, []byte{3}, NewPolynomial(nil), ErrLengthMismatch},
+		testrow{nil, []byte{1, 2, 1}, []byte{3, 4, 5}, NewPolynomial(nil), ErrDuplicatePoint},
+		testrow{Poly410_g2, []byte{1, 200}, []byte{1, 2}, NewPolynomial(Poly410_g2), ErrPointOutOfRange},
+		testrow{Poly410_g2, []byte{1, 2}, []byte{1, 200}, NewPolynomial(Poly410_g2), ErrPointOutOfRange},
+	} {
+		actual, err := Interpolate(row.field, row.xs, row.ys)
+		if err != row.err {
+			t.Errorf("[%2d] expected error %v, got %v", idx, row.err, err)
+		}
+		if !actual.Equal(row.expected) {
+			t.Errorf("[%2d] expected Interpolate(%v, %v) to be (%v), got (%v)",
+				idx, row.xs, row.ys, row.expected, actual)
+		}
+	}
+}
+
+func TestInterpolate_random(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		n := int(field.Size())
+		for trial := 0; trial < 32; trial++ {
+			xs := randomDistinct(prng, field, 1+prng.Intn(n))
+			p := make([]byte, prng.Intn(len(xs)+1))
+			for i := range p {
+				p[i] = byte(prng.Intn(n))
+			}
+			expected := NewPolynomial(field, p...)
+			ys := make([]byte, len(xs))
+			for i, x := range xs {
+				ys[i] = expected.Evaluate(x)
+			}
+
+			actual, err := Interpolate(field, xs, ys)
+			if err != nil || !actual.Equal(expected) {
+				t.Errorf("expected Interpolate to recover (%v), got (%v), %v",
+					expected, actual, err)
+			}
+
+			ni := NewNewtonInterpolator(field)
+			for i, x := range xs {
+				if err := ni.Add(x, ys[i]); err != nil {
+					t.Fatalf("NewtonInterpolator.Add(%d, %d): unexpected error %v", x, ys[i], err)
+				}
+				partial, _ := Interpolate(field, xs[:i+1], ys[:i+1])
+				if !ni.Polynomial().Equal(partial) {
+					t.Errorf("expected NewtonInterpolator after %d points to be (%v), got (%v)",
+						i+1, partial, ni.Polynomial())
+				}
+			}
+			for x := 0; x < n; x++ {
+				if actual, expected := ni.Evaluate(byte(x)), expected.Evaluate(byte(x)); actual != expected {
+					t.Errorf("NewtonInterpolator.Evaluate(%d): expected %d, got %d", x, expected, actual)
+				}
+			}
+		}
+	}
+}
+
+func TestNewtonInterpolator_duplicate(t *testing.T) {
+	ni := NewNewtonInterpolator(nil)
+	if err := ni.Add(3, 4); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	before := ni.Polynomial()
+	if err := ni.Add(3, 5); err != ErrDuplicatePoint {
+		t.Errorf("expected ErrDuplicatePoint, got %v", err)
+	}
+	if ni.Len() != 1 || !ni.Polynomial().Equal(before) {
+		t.Errorf("expected interpolator to be unchanged, got %d points, (%v)",
+			ni.Len(), ni.Polynomial())
+	}
+}
+
+func TestNewtonInterpolator_out_of_range(t *testing.T) {
+	ni := NewNewtonInterpolator(Poly410_g2)
+	if err := ni.Add(16, 1); err != ErrPointOutOfRange {
+		t.Errorf("expected ErrPointOutOfRange, got %v", err)
+	}
+	if err := ni.Add(1, 16); err != ErrPointOutOfRange {
+		t.Errorf("expected ErrPointOutOfRange, got %v", err)
+	}
+	if ni.Len() != 0 {
+		t.Errorf("expected interpolator to be unchanged, got %d points", ni.Len())
+	}
+}
+
+func randomDistinct(prng *rand.Rand, field *GF, count int) []byte {
+	perm := prng.Perm(int(field.Size()))
+	xs := make([]byte, count)
+	for i := range xs {
+		xs[i] = byte(perm[i])
+	}
+	return xs
+}
@@ -0,0 +1,238 @@
+package galoisfield
+
+import (
+	"math/big"
+	"math/rand"
+)
+
+// IsIrreducible returns true iff this polynomial has positive degree and
+// cannot be written as the product of two polynomials of lower degree.
+//
+// This is Rabin's test: a polynomial f of degree n over GF(q) is irreducible
+// iff f divides x**(q**n) - x, and gcd(f, x**(q**(n/p)) - x) = 1 for every
+// prime p dividing n.  All of the powers of x are computed by repeated
+// squaring, which is cheap in characteristic 2.
+func (a Polynomial) IsIrreducible() bool {
+	n := a.Degree()
+	if n == 0 {
+		return false
+	}
+	field := a.field
+	f := a.Monic()
+	x := NewPolynomial(field, 0, 1)
+	k := uint(field.k)
+	for _, p := range primeFactors(uint64(n)) {
+		h := wrapPolynomial(field, polyXPow2Mod(field, k*(n/uint(p)), f.coefficients))
+		if f.GCD(h.Add(x)).Degree() != 0 {
+			return false
+		}
+	}
+	h := wrapPolynomial(field, polyXPow2Mod(field, k*n, f.coefficients))
+	return h.Equal(x.Mod(f))
+}
+
+// isIrreducibleBenOr is equivalent to IsIrreducible, but uses Ben-Or's test,
+// which checks gcd(f, x**(q**i) - x) = 1 for each i ≤ n/2 in turn.  It does
+// more work than Rabin's test on irreducible inputs, but most reducible
+// polynomials have a small factor, so it rejects them early.  This makes it
+// the better choice when searching for irreducible polynomials at random.
+func isIrreducibleBenOr(f Polynomial) bool {
+	n := f.Degree()
+	if n == 0 {
+		return false
+	}
+	field := f.field
+	f = f.Monic()
+	x := NewPolynomial(field, 0, 1)
+	h := x.Mod(f)
+	for i := uint(1); 2*i <= n; i++ {
+		for j := byte(0); j < field.k; j++ {
+			h = wrapPolynomial(field, polySqrMod(field, h.coefficients, f.coefficients))
+		}
+		if f.GCD(h.Add(x)).Degree() != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// IsPrimitive returns true iff this polynomial is irreducible and its roots
+// generate the multiplicative group of the extension field they lie in.
+// Equivalently, x has multiplicative order q**n - 1 modulo this polynomial,
+// where n is its degree and q is the size of the coefficient field.
+//
+// Testing primitivity requires the prime factors of q**n - 1.  These are
+// computed (and cached) on demand, so the first test at a large degree may
+// be slow.
+func (a Polynomial) IsPrimitive() bool {
+	if !a.IsIrreducible() || a.Coefficient(0) == 0 {
+		return false
+	}
+	field := a.field
+	f := a.Monic()
+	x := []byte{0, 1}
+	bits := uint(field.k) * f.Degree()
+	order := new(big.Int).Lsh(big.NewInt(1), bits)
+	order.Sub(order, big.NewInt(1))
+	var e big.Int
+	for _, p := range mersenneFactors(bits) {
+		e.Quo(order, p)
+		r := polyPowMod(field, x, &e, f.coefficients)
+		if len(r) == 1 && r[0] == 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// RandomIrreducible returns a random monic irreducible polynomial of the given
+// degree with coefficients in the given field.  If rng is nil, the default
+// source of the math/rand package is used.  It panics with ErrPolyOutOfRange
+// if degree is 0.
+//
+// About one in every n monic polynomials of degree n is irreducible, so the
+// expected number of candidates tested is about n.
+func RandomIrreducible(field *GF, degree uint, rng *rand.Rand) Polynomial {
+	if field == nil {
+		field = Default
+	}
+	if degree == 0 {
+		panic(ErrPolyOutOfRange)
+	}
+	intn := rand.Intn
+	if rng != nil {
+		intn = rng.Intn
+	}
+	coefficients := make([]byte, degree+1)
+	for {
+		for i := uint(0); i < degree; i++ {
+			coefficients[i] = byte(intn(int(field.Size())))
+		}
+		coefficients[degree] = 1
+		p := NewPolynomial(field, coefficients...)
+		if isIrreducibleBenOr(p) {
+			return p
+		}
+	}
+}
+
+// LowWeightIrreducible returns a monic irreducible polynomial of the given
+// degree with as few non-zero terms as possible.  Among the candidates of
+// minimal weight, it prefers those whose coefficients are all 1 (such as the
+// binary trinomials and pentanomials used by standards), then those whose
+// middle terms have the lowest degrees.  It panics with ErrPolyOutOfRange if
+// degree is 0.
+//
+// Sparse moduli make reduction cheap: reducing by x**n + x**t + 1 takes two
+// shifted additions per term, regardless of n.
+func LowWeightIrreducible(field *GF, degree uint) Polynomial {
+	if field == nil {
+		field = Default
+	}
+	if degree == 0 {
+		panic(ErrPolyOutOfRange)
+	}
+	if degree == 1 {
+		return NewPolynomial(field, 0, 1)
+	}
+	coefficients := make([]byte, degree+1)
+	test := func(positions []uint, values []byte) bool {
+		for i := range coefficients {
+			coefficients[i] = 0
+		}
+		coefficients[degree] = 1
+		for i, pos := range positions {
+			coefficients[pos] = values[i]
+		}
+		return isIrreducibleBenOr(NewPolynomial(field, coefficients...))
+	}
+	for weight := uint(2); weight <= degree+1; weight++ {
+		// positions[0] is always the constant term, which must be
+		// non-zero or else x would be a factor.
+		for _, onesOnly := range []bool{true, false} {
+			if forEachSubset(1, degree-1, weight-2, func(middle []uint) bool {
+				positions := append([]uint{0}, middle...)
+				return forEachCoefficients(field, degree, positions, onesOnly, func(values []byte) bool {
+					return test(positions, values)
+				})
+			}) {
+				return NewPolynomial(field, coefficients...)
+			}
+		}
+	}
+	panic("BUG: no irreducible polynomial found")
+}
+
+// forEachSubset calls fn with each size-r subset of [lo, hi] in ascending
+// lexicographic order, stopping early if fn returns true.
+func forEachSubset(lo, hi, r uint, fn func([]uint) bool) bool {
+	subset := make([]uint, r)
+	var recurse func(i, next uint) bool
+	recurse = func(i, next uint) bool {
+		if i == r {
+			return fn(subset)
+		}
+		for v := next; v+(r-i-1) <= hi; v++ {
+			subset[i] = v
+			if recurse(i+1, v+1) {
+				return true
+			}
+		}
+		return false
+	}
+	return recurse(0, lo)
+}
+
+// forEachCoefficients calls fn with each assignment of non-zero coefficients
+// to the given (ascending) positions of a monic polynomial of degree n,
+// stopping early if fn returns true.  If onesOnly is set, only the all-ones
+// assignment is tried.
+//
+// Substituting λ*x for x and rescaling maps a*x**t to a*λ**(t-n)*x**t while
+// preserving irreducibility, so the coefficient at the highest position t
+// only needs to range over representatives g**j, j < gcd(n-t, q-1), of the
+// cosets of the (n-t)'th powers.  This cuts the search by a factor of almost q.
+func forEachCoefficients(field *GF, n uint, positions []uint, onesOnly bool, fn func([]byte) bool) bool {
+	values := make([]byte, len(positions))
+	for i := range values {
+		values[i] = 1
+	}
+	if onesOnly {
+		return fn(values)
+	}
+	last := len(values) - 1
+	limits := make([]uint, len(values))
+	for i := range limits {
+		limits[i] = field.m
+	}
+	limits[last] = gcd(n-positions[last], field.m)
+	index := make([]uint, len(values))
+	for {
+		if fn(values) {
+			return true
+		}
+		i := 0
+		for i < len(index) && index[i]+1 == limits[i] {
+			index[i] = 0
+			values[i] = 1
+			i++
+		}
+		if i == len(index) {
+			return false
+		}
+		index[i]++
+		if i == last {
+			values[i] = field.exp[index[i]]
+		} else {
+			values[i] = byte(index[i] + 1)
+		}
+	}
+}
+
+// gcd returns the greatest common divisor of a and b.
+func gcd(a, b uint) uint {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
@@ -0,0 +1,161 @@
+package galoisfield
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestPolynomial_IsIrreducible_benOr(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		n := int(field.Size())
+		for trial := 0; trial < 64; trial++ {
+			var p []byte
+			for i := 1 + prng.Intn(10); i >= 0; i-- {
+				p = append(p, byte(prng.Intn(n)))
+			}
+			p = append(p, 1)
+			poly := NewPolynomial(field, p...)
+			rabin := poly.IsIrreducible()
+			benOr := isIrreducibleBenOr(poly)
+			if rabin != benOr {
+				t.Errorf("expected Rabin and Ben-Or to agree for (%v) over %v, got %v vs %v",
+					poly, field, rabin, benOr)
+			}
+		}
+	}
+}
+
+func TestPolynomial_IsPrimitive(t *testing.T) {
+	// The number of monic primitive polynomials of degree n over GF(q) is
+	// φ(q**n - 1)/n.
+	type testrow struct {
+		field    *GF
+		degree   uint
+		expected int
+	}
+	for _, row := range []testrow{
+		testrow{Poly210_g2, 1, 2},
+		testrow{Poly210_g2, 2, 4},
+		testrow{Poly210_g2, 3, 12},
+		testrow{Poly310_g2, 1, 6},
+		testrow{Poly310_g2, 2, 18},
+		testrow{Poly410_g2, 2, 64},
+	} {
+		q := row.field.Size()
+		total := uint(1)
+		for i := uint(0); i < row.degree; i++ {
+			total *= q
+		}
+		count := 0
+		for i := uint(0); i < total; i++ {
+			coefficients := make([]byte, row.degree+1)
+			for j, v := uint(0), i; j < row.degree; j, v = j+1, v/q {
+				coefficients[j] = byte(v % q)
+			}
+			coefficients[row.degree] = 1
+			if NewPolynomial(row.field, coefficients...).IsPrimitive() {
+				count++
+			}
+		}
+		if count != row.expected {
+			t.Errorf("expected %d monic primitives of degree %d over %v, got %d",
+				row.expected, row.degree, row.field, count)
+		}
+	}
+
+	// x - g is primitive iff g generates the field.
+	for _, field := range fields {
+		g := byte(field.Generator())
+		if !NewPolynomial(field, g, 1).IsPrimitive() {
+			t.Errorf("expected x+%d to be primitive over %v", g, field)
+		}
+	}
+}
+
+func TestRandomIrreducible(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		for degree := uint(1); degree <= 12; degree++ {
+			p := RandomIrreducible(field, degree, prng)
+			if p.Field() != field || p.Degree() != degree || p.LeadingCoefficient() != 1 {
+				t.Errorf("expected monic polynomial of degree %d over %v, got (%v)",
+					degree, field, p)
+			}
+			if !p.IsIrreducible() {
+				t.Errorf("expected irreducible polynomial, got (%v) over %v",
+					p, field)
+			}
+		}
+	}
+	e := panicValue(func() {
+		RandomIrreducible(nil, 0, nil)
+	})
+	if e != ErrPolyOutOfRange {
+		t.Errorf("expected panic(ErrPolyOutOfRange), got %v", e)
+	}
+}
+
+func TestLowWeightIrreducible(t *testing.T) {
+	type testrow struct {
+		field    *GF
+		degree   uint
+		expected Polynomial
+	}
+	for _, row := range []testrow{
+		testrow{Poly210_g2, 1, NewPolynomial(Poly210_g2, 0, 1)},
+		testrow{Poly210_g2, 2, NewPolynomial(Poly210_g2, 2, 1, 1)},
+		testrow{Poly210_g2, 3, NewPolynomial(Poly210_g2, 2, 0, 0, 1)},
+		testrow{Poly210_g2, 5, NewPolynomial(Poly210_g2, 1, 0, 1, 0, 0, 1)},
+		testrow{Poly310_g2, 4, NewPolynomial(Poly310_g2, 1, 1, 0, 0, 1)},
+	} {
+		actual := LowWeightIrreducible(row.field, row.degree)
+		if !actual.Equal(row.expected) {
+			t.Errorf("expected lowest-weight irreducible of degree %d over %v to be (%v), got (%v)",
+				row.degree, row.field, row.expected, actual)
+		}
+	}
+	for _, field := range fields {
+		for degree := uint(1); degree <= 16; degree++ {
+			p := LowWeightIrreducible(field, degree)
+			if p.Degree() != degree || p.LeadingCoefficient() != 1 || !p.IsIrreducible() {
+				t.Errorf("expected monic irreducible of degree %d over %v, got (%v)",
+					degree, field, p)
+			}
+		}
+	}
+}
+
+func TestIsReducible(t *testing.T) {
+	for p := uint(4); p < 1024; p++ {
+		expected := isReducibleTrialDivision(p)
+		actual := isReducible(p)
+		if actual != expected {
+			t.Errorf("isReducible(%#x): expected %v, got %v", p, expected, actual)
+		}
+	}
+}
+
+func BenchmarkIsReducible(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = isReducible(0x11d)
+	}
+}
+
+func BenchmarkIsReducible_trialDivision(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = isReducibleTrialDivision(0x11d)
+	}
+}
+
+// isReducibleTrialDivision is the original implementation of isReducible,
+// which tries every divisor up to half the degree.
+func isReducibleTrialDivision(p uint) bool {
+	var n uint = 1 << ((degree(p) / 2) + 1)
+	for divisor := uint(2); divisor < n; divisor++ {
+		if polyDiv(p, divisor) == 0 {
+			return true
+		}
+	}
+	return false
+}
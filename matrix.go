@@ -0,0 +1,472 @@
+package galoisfield
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+)
+
+var (
+	ErrDimensionMismatch = errors.New("matrix dimensions do not match")
+	ErrSingular          = errors.New("matrix is singular")
+)
+
+// Matrix implements matrices with entries drawn from a Galois field.
+//
+// Like Polynomial, Matrix is an immutable value type: every operation
+// returns a new matrix.  Operations panic with ErrDimensionMismatch if the
+// shapes of their operands are incompatible, or with ErrIncompatibleFields
+// if the operands are drawn from different fields.
+type Matrix struct {
+	field      *GF
+	rows, cols int
+	data       []byte // row-major
+}
+
+// NewMatrix returns a new rows×cols matrix with the given entries, in
+// row-major order.  The entries are copied; missing entries are zero.  If
+// field is nil, Default is used.  It panics with ErrDimensionMismatch if
+// there are more than rows*cols entries.
+func NewMatrix(field *GF, rows, cols int, entries ...byte) Matrix {
+	if field == nil {
+		field = Default
+	}
+	if rows < 0 || cols < 0 || len(entries) > rows*cols {
+		panic(ErrDimensionMismatch)
+	}
+	data := make([]byte, rows*cols)
+	copy(data, entries)
+	return Matrix{field, rows, cols, data}
+}
+
+// NewMatrixFromRows returns a new matrix with the given rows, which are
+// copied.  If field is nil, Default is used.  It panics with
+// ErrDimensionMismatch if the rows differ in length.
+func NewMatrixFromRows(field *GF, rows ...[]byte) Matrix {
+	cols := 0
+	if len(rows) > 0 {
+		cols = len(rows[0])
+	}
+	m := NewMatrix(field, len(rows), cols)
+	for i, row := range rows {
+		if len(row) != cols {
+			panic(ErrDimensionMismatch)
+		}
+		copy(m.row(i), row)
+	}
+	return m
+}
+
+// Identity returns the n×n identity matrix.  If field is nil, Default is
+// used.
+func Identity(field *GF, n int) Matrix {
+	m := NewMatrix(field, n, n)
+	for i := 0; i < n; i++ {
+		m.data[i*n+i] = 1
+	}
+	return m
+}
+
+// Field returns the Galois field from which this matrix's entries are drawn.
+func (m Matrix) Field() *GF { return m.field }
+
+// Rows returns the number of rows.
+func (m Matrix) Rows() int { return m.rows }
+
+// Cols returns the number of columns.
+func (m Matrix) Cols() int { return m.cols }
+
+// At returns the entry in row i and column j.
+func (m Matrix) At(i, j int) byte {
+	if i < 0 || i >= m.rows || j < 0 || j >= m.cols {
+		panic(ErrDimensionMismatch)
+	}
+	return m.data[i*m.cols+j]
+}
+
+// Row returns a copy of row i.
+func (m Matrix) Row(i int) []byte {
+	if i < 0 || i >= m.rows {
+		panic(ErrDimensionMismatch)
+	}
+	return append([]byte(nil), m.row(i)...)
+}
+
+// Col returns a copy of column j.
+func (m Matrix) Col(j int) []byte {
+	if j < 0 || j >= m.cols {
+		panic(ErrDimensionMismatch)
+	}
+	col := make([]byte, m.rows)
+	for i := range col {
+		col[i] = m.data[i*m.cols+j]
+	}
+	return col
+}
+
+// IsSquare returns true iff this matrix has as many rows as columns.
+func (m Matrix) IsSquare() bool { return m.rows == m.cols }
+
+// Add returns the sum of one or more matrices of the same shape.
+func (first Matrix) Add(rest ...Matrix) Matrix {
+	sum := first.clone()
+	for _, next := range rest {
+		first.checkField(next)
+		if next.rows != first.rows || next.cols != first.cols {
+			panic(ErrDimensionMismatch)
+		}
+		for i, v := range next.data {
+			sum.data[i] ^= v
+		}
+	}
+	return sum
+}
+
+// Scale returns the product of this matrix with the scalar s.
+func (m Matrix) Scale(s byte) Matrix {
+	scaled := NewMatrix(m.field, m.rows, m.cols)
+	addScaled(m.field, scaled.data, m.data, s)
+	return scaled
+}
+
+// Mul returns the product of one or more matrices.
+func (first Matrix) Mul(rest ...Matrix) Matrix {
+	prod := first
+	for _, next := range rest {
+		first.checkField(next)
+		if prod.cols != next.rows {
+			panic(ErrDimensionMismatch)
+		}
+		out := NewMatrix(first.field, prod.rows, next.cols)
+		for i := 0; i < prod.rows; i++ {
+			dst := out.row(i)
+			for k, a := range prod.row(i) {
+				addScaled(first.field, dst, next.row(k), a)
+			}
+		}
+		prod = out
+	}
+	return prod
+}
+
+// MulVec returns the matrix-vector product m*v.
+func (m Matrix) MulVec(v []byte) []byte {
+	if len(v) != m.cols {
+		panic(ErrDimensionMismatch)
+	}
+	out := make([]byte, m.rows)
+	for i := range out {
+		var sum byte
+		for j, a := range m.row(i) {
+			sum ^= m.field.Mul(a, v[j])
+		}
+		out[i] = sum
+	}
+	return out
+}
+
+// MulShards computes the matrix product of m with the shards in src, and
+// stores it in dst: each dst[i] is overwritten with the sum over j of
+// m[i][j] * src[j], where the products are taken entry by entry.  This is
+// how erasure codes encode and decode data split into equal-sized shards.
+//
+// There must be one src shard per column and one dst shard per row, all of
+// the same length; otherwise it panics with ErrDimensionMismatch.  The dst
+// shards must not overlap the src shards.
+func (m Matrix) MulShards(dst, src [][]byte) {
+	if len(src) != m.cols || len(dst) != m.rows {
+		panic(ErrDimensionMismatch)
+	}
+	n := -1
+	for _, shards := range [][][]byte{src, dst} {
+		for _, shard := range shards {
+			if n >= 0 && len(shard) != n {
+				panic(ErrDimensionMismatch)
+			}
+			n = len(shard)
+		}
+	}
+	for i, out := range dst {
+		for k := range out {
+			out[k] = 0
+		}
+		for j, a := range m.row(i) {
+			addScaled(m.field, out, src[j], a)
+		}
+	}
+}
+
+// Transpose returns the transpose of this matrix.
+func (m Matrix) Transpose() Matrix {
+	t := NewMatrix(m.field, m.cols, m.rows)
+	for i := 0; i < m.rows; i++ {
+		for j, v := range m.row(i) {
+			t.data[j*m.rows+i] = v
+		}
+	}
+	return t
+}
+
+// SubMatrix returns the matrix formed from the given rows and columns of m,
+// in the given order.  A nil slice selects every row or column.  For
+// example, the decoding matrix of an erasure code is the sub-matrix of its
+// generator formed by the surviving rows.
+func (m Matrix) SubMatrix(rows, cols []int) Matrix {
+	rows, cols = allIndices(rows, m.rows), allIndices(cols, m.cols)
+	sub := NewMatrix(m.field, len(rows), len(cols))
+	for i, r := range rows {
+		if r < 0 || r >= m.rows {
+			panic(ErrDimensionMismatch)
+		}
+		src, dst := m.row(r), sub.row(i)
+		for j, c := range cols {
+			if c < 0 || c >= m.cols {
+				panic(ErrDimensionMismatch)
+			}
+			dst[j] = src[c]
+		}
+	}
+	return sub
+}
+
+// RowEchelon returns a row echelon form of this matrix, obtained by Gaussian
+// elimination: the leading entry of each non-zero row is 1, and lies to the
+// right of the leading entry of the row above.  Zero rows come last.
+func (m Matrix) RowEchelon() Matrix {
+	e := m.clone()
+	e.eliminate(false)
+	return e
+}
+
+// Rank returns the rank of this matrix.
+func (m Matrix) Rank() int {
+	return len(m.clone().eliminate(false))
+}
+
+// Determinant returns the determinant of this square matrix.  It panics with
+// ErrDimensionMismatch if the matrix is not square.
+//
+// In characteristic 2 row swaps do not change the sign, so the determinant
+// is the product of the pivots found by Gaussian elimination.
+func (m Matrix) Determinant() byte {
+	if !m.IsSquare() {
+		panic(ErrDimensionMismatch)
+	}
+	e := m.clone()
+	var det byte = 1
+	for r := 0; r < e.rows; r++ {
+		p := r
+		for p < e.rows && e.data[p*e.cols+r] == 0 {
+			p++
+		}
+		if p == e.rows {
+			return 0
+		}
+		e.swapRows(r, p)
+		pivot := e.data[r*e.cols+r]
+		det = m.field.Mul(det, pivot)
+		inv := m.field.Inv(pivot)
+		for i := r + 1; i < e.rows; i++ {
+			if c := e.data[i*e.cols+r]; c != 0 {
+				addScaled(m.field, e.row(i)[r:], e.row(r)[r:], m.field.Mul(c, inv))
+			}
+		}
+	}
+	return det
+}
+
+// Inverse returns the inverse of this square matrix, or ErrSingular if it
+// has none.  It panics with ErrDimensionMismatch if the matrix is not square.
+func (m Matrix) Inverse() (Matrix, error) {
+	if !m.IsSquare() {
+		panic(ErrDimensionMismatch)
+	}
+	n := m.rows
+	aug := m.augment(Identity(m.field, n))
+	if !leadingPivots(aug.eliminate(true), n) {
+		return Matrix{}, ErrSingular
+	}
+	return aug.SubMatrix(nil, columnRange(n, 2*n)), nil
+}
+
+// Solve returns the solution x of m*x = b for a square matrix m, or
+// ErrSingular if m is singular.  It panics with ErrDimensionMismatch if m is
+// not square or b has the wrong length.
+func (m Matrix) Solve(b []byte) ([]byte, error) {
+	if !m.IsSquare() || len(b) != m.rows {
+		panic(ErrDimensionMismatch)
+	}
+	n := m.rows
+	aug := m.augment(NewMatrix(m.field, n, 1, b...))
+	if !leadingPivots(aug.eliminate(true), n) {
+		return nil, ErrSingular
+	}
+	return aug.Col(n), nil
+}
+
+// Compare defines a total order for matrices: -1 if a < b, 0 if a == b, +1
+// if a > b.  Matrices are ordered by field, then shape, then entries in
+// row-major order.
+func (a Matrix) Compare(b Matrix) int {
+	if cmp := a.field.Compare(b.field); cmp != 0 {
+		return cmp
+	}
+	switch {
+	case a.rows < b.rows:
+		return -1
+	case a.rows > b.rows:
+		return 1
+	case a.cols < b.cols:
+		return -1
+	case a.cols > b.cols:
+		return 1
+	}
+	return bytes.Compare(a.data, b.data)
+}
+
+// Equal returns true iff a == b.
+func (a Matrix) Equal(b Matrix) bool {
+	return a.Compare(b) == 0
+}
+
+// Less returns true iff a < b.
+func (a Matrix) Less(b Matrix) bool {
+	return a.Compare(b) < 0
+}
+
+// GoString returns a Go-syntax representation of this matrix.
+func (m Matrix) GoString() string {
+	var buf bytes.Buffer
+	buf.WriteString("NewMatrix(")
+	buf.WriteString(m.field.GoString())
+	buf.WriteString(", ")
+	buf.WriteString(strconv.Itoa(m.rows))
+	buf.WriteString(", ")
+	buf.WriteString(strconv.Itoa(m.cols))
+	for _, v := range m.data {
+		buf.WriteString(", ")
+		buf.WriteString(strconv.Itoa(int(v)))
+	}
+	buf.WriteByte(')')
+	return buf.String()
+}
+
+// String returns a human-readable representation of this matrix, with rows
+// separated by semicolons, such as "[1 2; 3 4]".
+func (m Matrix) String() string {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i := 0; i < m.rows; i++ {
+		if i > 0 {
+			buf.WriteString("; ")
+		}
+		for j, v := range m.row(i) {
+			if j > 0 {
+				buf.WriteByte(' ')
+			}
+			buf.WriteString(strconv.Itoa(int(v)))
+		}
+	}
+	buf.WriteByte(']')
+	return buf.String()
+}
+
+// eliminate brings m to row echelon form in place, or to reduced row echelon
+// form if reduced is set, and returns the pivot columns.
+func (m Matrix) eliminate(reduced bool) []int {
+	field := m.field
+	var pivots []int
+	r := 0
+	for c := 0; c < m.cols && r < m.rows; c++ {
+		p := r
+		for p < m.rows && m.data[p*m.cols+c] == 0 {
+			p++
+		}
+		if p == m.rows {
+			continue
+		}
+		m.swapRows(r, p)
+		pivot := m.row(r)[c:]
+		if pivot[0] != 1 {
+			inv := field.Inv(pivot[0])
+			for j, v := range pivot {
+				pivot[j] = field.Mul(v, inv)
+			}
+		}
+		start := r + 1
+		if reduced {
+			start = 0
+		}
+		for i := start; i < m.rows; i++ {
+			if i == r {
+				continue
+			}
+			if s := m.data[i*m.cols+c]; s != 0 {
+				addScaled(field, m.row(i)[c:], pivot, s)
+			}
+		}
+		pivots = append(pivots, c)
+		r++
+	}
+	return pivots
+}
+
+// leadingPivots returns true iff the first n pivot columns are 0, ..., n-1,
+// i.e. the left n columns of an augmented matrix are non-singular.
+func leadingPivots(pivots []int, n int) bool {
+	return len(pivots) >= n && (n == 0 || pivots[n-1] == n-1)
+}
+
+// augment returns the matrix [m | b].
+func (m Matrix) augment(b Matrix) Matrix {
+	if b.rows != m.rows {
+		panic(ErrDimensionMismatch)
+	}
+	aug := NewMatrix(m.field, m.rows, m.cols+b.cols)
+	for i := 0; i < m.rows; i++ {
+		copy(aug.row(i), m.row(i))
+		copy(aug.row(i)[m.cols:], b.row(i))
+	}
+	return aug
+}
+
+func (m Matrix) row(i int) []byte {
+	return m.data[i*m.cols : (i+1)*m.cols : (i+1)*m.cols]
+}
+
+func (m Matrix) swapRows(i, j int) {
+	if i == j {
+		return
+	}
+	a, b := m.row(i), m.row(j)
+	for k := range a {
+		a[k], b[k] = b[k], a[k]
+	}
+}
+
+func (m Matrix) clone() Matrix {
+	return Matrix{m.field, m.rows, m.cols, append([]byte(nil), m.data...)}
+}
+
+func (m Matrix) checkField(b Matrix) {
+	if m.field != b.field {
+		panic(ErrIncompatibleFields)
+	}
+}
+
+// allIndices returns indices, or 0, 1, ..., n-1 if indices is nil.
+func allIndices(indices []int, n int) []int {
+	if indices != nil {
+		return indices
+	}
+	return columnRange(0, n)
+}
+
+// columnRange returns lo, lo+1, ..., hi-1.
+func columnRange(lo, hi int) []int {
+	r := make([]int, hi-lo)
+	for i := range r {
+		r[i] = lo + i
+	}
+	return r
+}
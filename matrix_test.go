@@ -0,0 +1,240 @@
+package galoisfield
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestMatrix_String(t *testing.T) {
+	type testrow struct {
+		input Matrix
+		str   string
+		gostr string
+	}
+	for idx, row := range []testrow{
+		testrow{NewMatrix(nil, 0, 0), "[]", "NewMatrix(Poly84320_g2, 0, 0)"},
+		testrow{NewMatrix(nil, 2, 2, 1, 2, 3, 4), "[1 2; 3 4]", "NewMatrix(Poly84320_g2, 2, 2, 1, 2, 3, 4)"},
+		testrow{NewMatrix(Poly210_g2, 1, 3, 3), "[3 0 0]", "NewMatrix(Poly210_g2, 1, 3, 3, 0, 0)"},
+		testrow{Identity(nil, 2).Transpose(), "[1 0; 0 1]", "NewMatrix(Poly84320_g2, 2, 2, 1, 0, 0, 1)"},
+	} {
+		if actual := row.input.String(); actual != row.str {
+			t.Errorf("[%d] String: expected %q, got %q", idx, row.str, actual)
+		}
+		if actual := row.input.GoString(); actual != row.gostr {
+			t.Errorf("[%d] GoString: expected %q, got %q", idx, row.gostr, actual)
+		}
+	}
+}
+
+func TestMatrix_Mul(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		for trial := 0; trial < 16; trial++ {
+			r, k, c := 1+prng.Intn(6), 1+prng.Intn(6), 1+prng.Intn(6)
+			a := randomMatrix(prng, field, r, k)
+			b := randomMatrix(prng, field, k, c)
+			ab := a.Mul(b)
+			for i := 0; i < r; i++ {
+				for j := 0; j < c; j++ {
+					var sum byte
+					for l := 0; l < k; l++ {
+						sum ^= field.Mul(a.At(i, l), b.At(l, j))
+					}
+					if ab.At(i, j) != sum {
+						t.Errorf("%v: (%v)*(%v) at %d,%d: expected %d, got %d",
+							field, a, b, i, j, sum, ab.At(i, j))
+					}
+				}
+			}
+			if !ab.Transpose().Equal(b.Transpose().Mul(a.Transpose())) {
+				t.Errorf("%v: (AB)^T != B^T A^T for (%v), (%v)", field, a, b)
+			}
+			if !a.Mul(Identity(field, k)).Equal(a) || !Identity(field, r).Mul(a).Equal(a) {
+				t.Errorf("%v: identity fails for (%v)", field, a)
+			}
+			v := randomCoefficients(prng, int(field.Size()), c)
+			if expected, actual := ab.Col(0), a.MulVec(b.Col(0)); !equalBytes(expected, actual) {
+				t.Errorf("%v: MulVec: expected %v, got %v", field, expected, actual)
+			}
+			if !equalBytes(ab.MulVec(v), a.MulVec(b.MulVec(v))) {
+				t.Errorf("%v: MulVec is not associative for (%v), (%v)", field, a, b)
+			}
+			if !a.Add(a).Equal(NewMatrix(field, r, k)) || !a.Scale(1).Equal(a) {
+				t.Errorf("%v: Add/Scale fail for (%v)", field, a)
+			}
+		}
+	}
+}
+
+func TestMatrix_MulShards(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	m := randomMatrix(prng, nil, 3, 4)
+	src := make([][]byte, 4)
+	for i := range src {
+		src[i] = randomCoefficients(prng, 256, 100)
+	}
+	dst := make([][]byte, 3)
+	for i := range dst {
+		dst[i] = randomCoefficients(prng, 256, 100)
+	}
+	m.MulShards(dst, src)
+	for k := 0; k < 100; k++ {
+		column := make([]byte, 4)
+		for j := range column {
+			column[j] = src[j][k]
+		}
+		expected := m.MulVec(column)
+		for i := range dst {
+			if dst[i][k] != expected[i] {
+				t.Fatalf("shard %d, byte %d: expected %d, got %d", i, k, expected[i], dst[i][k])
+			}
+		}
+	}
+}
+
+func TestMatrix_SubMatrix(t *testing.T) {
+	m := NewMatrix(nil, 3, 3, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+	type testrow struct {
+		rows, cols []int
+		expected   string
+	}
+	for idx, row := range []testrow{
+		testrow{nil, nil, "[1 2 3; 4 5 6; 7 8 9]"},
+		testrow{[]int{2, 0}, nil, "[7 8 9; 1 2 3]"},
+		testrow{nil, []int{1}, "[2; 5; 8]"},
+		testrow{[]int{1, 1}, []int{2, 0}, "[6 4; 6 4]"},
+		testrow{[]int{}, nil, "[]"},
+	} {
+		if actual := m.SubMatrix(row.rows, row.cols).String(); actual != row.expected {
+			t.Errorf("[%d] expected %q, got %q", idx, row.expected, actual)
+		}
+	}
+}
+
+func TestMatrix_Inverse(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		for trial := 0; trial < 32; trial++ {
+			n := prng.Intn(8)
+			m := randomMatrix(prng, field, n, n)
+			det := m.Determinant()
+			inv, err := m.Inverse()
+			rank := m.Rank()
+			b := randomCoefficients(prng, int(field.Size()), n)
+			x, serr := m.Solve(b)
+			if det == 0 {
+				if err != ErrSingular || serr != ErrSingular || rank == n {
+					t.Errorf("%v: expected (%v) to be singular, got %v, %v, rank %d",
+						field, m, err, serr, rank)
+				}
+				continue
+			}
+			if err != nil || serr != nil || rank != n {
+				t.Errorf("%v: expected (%v) to be invertible, got %v, %v, rank %d",
+					field, m, err, serr, rank)
+				continue
+			}
+			if !m.Mul(inv).Equal(Identity(field, n)) || !inv.Mul(m).Equal(Identity(field, n)) {
+				t.Errorf("%v: (%v) * (%v) is not the identity", field, m, inv)
+			}
+			if !equalBytes(m.MulVec(x), b) {
+				t.Errorf("%v: (%v) * %v != %v", field, m, x, b)
+			}
+			if d := inv.Determinant(); field.Mul(d, det) != 1 {
+				t.Errorf("%v: det(M**-1) = %d, expected 1/%d", field, d, det)
+			}
+		}
+	}
+}
+
+func TestMatrix_Determinant(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		for trial := 0; trial < 16; trial++ {
+			n := 1 + prng.Intn(6)
+			a, b := randomMatrix(prng, field, n, n), randomMatrix(prng, field, n, n)
+			if expected, actual := field.Mul(a.Determinant(), b.Determinant()), a.Mul(b).Determinant(); expected != actual {
+				t.Errorf("%v: det(AB) = %d, expected det(A)det(B) = %d", field, actual, expected)
+			}
+			if a.Determinant() != a.Transpose().Determinant() {
+				t.Errorf("%v: det(A) != det(A^T) for (%v)", field, a)
+			}
+		}
+	}
+	// det [a b; c d] = ad + bc.
+	m := NewMatrix(nil, 2, 2, 3, 5, 7, 11)
+	if expected := Default.Mul(3, 11) ^ Default.Mul(5, 7); m.Determinant() != expected {
+		t.Errorf("expected det (%v) = %d, got %d", m, expected, m.Determinant())
+	}
+}
+
+func TestMatrix_RowEchelon(t *testing.T) {
+	m := NewMatrix(nil, 3, 4, 0, 2, 4, 6, 0, 1, 2, 3, 1, 1, 1, 1)
+	e := m.RowEchelon()
+	if e.Rank() != 2 || m.Rank() != 2 {
+		t.Errorf("expected rank 2 for (%v), got %d", m, m.Rank())
+	}
+	lead := -1
+	for i := 0; i < e.Rows(); i++ {
+		j := 0
+		for j < e.Cols() && e.At(i, j) == 0 {
+			j++
+		}
+		if j == e.Cols() {
+			lead = e.Cols()
+			continue
+		}
+		if j <= lead || e.At(i, j) != 1 {
+			t.Errorf("(%v) is not in row echelon form", e)
+		}
+		lead = j
+	}
+}
+
+func TestMatrix_panics(t *testing.T) {
+	type testrow struct {
+		fn       func()
+		expected error
+	}
+	a := NewMatrix(nil, 2, 3)
+	for idx, row := range []testrow{
+		testrow{func() { NewMatrix(nil, 1, 1, 1, 2) }, ErrDimensionMismatch},
+		testrow{func() { NewMatrixFromRows(nil, []byte{1}, []byte{1, 2}) }, ErrDimensionMismatch},
+		testrow{func() { a.Mul(a) }, ErrDimensionMismatch},
+		testrow{func() { a.Add(a.Transpose()) }, ErrDimensionMismatch},
+		testrow{func() { a.Add(NewMatrix(Poly210_g2, 2, 3)) }, ErrIncompatibleFields},
+		testrow{func() { a.MulVec([]byte{1, 2}) }, ErrDimensionMismatch},
+		testrow{func() { a.Determinant() }, ErrDimensionMismatch},
+		testrow{func() { a.Inverse() }, ErrDimensionMismatch},
+		testrow{func() { Identity(nil, 2).Solve([]byte{1}) }, ErrDimensionMismatch},
+		testrow{func() { a.At(2, 0) }, ErrDimensionMismatch},
+		testrow{func() { a.SubMatrix([]int{5}, nil) }, ErrDimensionMismatch},
+		testrow{func() {
+			a.MulShards([][]byte{{1}, {1, 2}}, [][]byte{{1}, {1}, {1}})
+		}, ErrDimensionMismatch},
+	} {
+		if e := panicValue(row.fn); e != row.expected {
+			t.Errorf("[%d] expected panic(%v), got %v", idx, row.expected, e)
+		}
+	}
+}
+
+func randomMatrix(prng *rand.Rand, field *GF, rows, cols int) Matrix {
+	if field == nil {
+		field = Default
+	}
+	entries := make([]byte, rows*cols)
+	for i := range entries {
+		entries[i] = byte(prng.Intn(int(field.Size())))
+	}
+	return NewMatrix(field, rows, cols, entries...)
+}
+
+func BenchmarkMatrix_Inverse(b *testing.B) {
+	prng := rand.New(rand.NewSource(42))
+	m := randomMatrix(prng, nil, 64, 64)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Inverse()
+	}
+}
@@ -0,0 +1,281 @@
+package galoisfield
+
+// Operand lengths at which polyMul switches from the schoolbook method to
+// Karatsuba, and from Karatsuba to Toom-3.  These were tuned with
+// BenchmarkPolyMul on GF(256).
+const (
+	karatsubaMin = 32
+	toom3Min     = 384
+)
+
+// polyMul returns the reduced product a*b.
+func polyMul(field *GF, a, b []byte) []byte {
+	if len(a) == 0 || len(b) == 0 {
+		return nil
+	}
+	prod := make([]byte, len(a)+len(b)-1)
+	mulInto(field, prod, a, b, nil)
+	return reduce(prod)
+}
+
+// mulInto sets dst to a*b, where a and b are non-empty and dst has length
+// len(a)+len(b)-1 and does not overlap either operand.  It returns the
+// scratch buffer, which is reused if it is large enough and reallocated
+// otherwise, so that callers can keep it for the next multiplication.
+//
+// Short operands are multiplied with the schoolbook method.  Longer ones are
+// cut into chunks as long as the shorter operand, and each balanced product
+// is computed with Karatsuba's method or Toom-3.  All of the intermediate
+// results share the one scratch buffer.
+func mulInto(field *GF, dst, a, b, scratch []byte) []byte {
+	for i := range dst {
+		dst[i] = 0
+	}
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+	n := len(b)
+	if n < karatsubaMin {
+		mulSchoolbook(field, dst, a, b)
+		return scratch
+	}
+
+	need := 2*n - 1 + n + mulScratchSize(n)
+	if cap(scratch) < need {
+		scratch = make([]byte, need)
+	}
+	buf := scratch[:need]
+	chunkProd, buf := buf[:2*n-1], buf[2*n-1:]
+	padded, buf := buf[:n], buf[n:]
+	for off := 0; off < len(a); off += n {
+		chunk := a[off:]
+		if len(chunk) >= n {
+			chunk = chunk[:n]
+		} else {
+			copy(padded, chunk)
+			for i := len(chunk); i < n; i++ {
+				padded[i] = 0
+			}
+			chunk = padded
+		}
+		mulBalanced(field, chunkProd, chunk, b, buf)
+		out := dst[off:]
+		for i := 0; i < len(chunkProd) && i < len(out); i++ {
+			out[i] ^= chunkProd[i]
+		}
+	}
+	return scratch
+}
+
+// mulScratchSize returns the length of the scratch buffer needed by
+// mulBalanced for operands of length n.
+func mulScratchSize(n int) int {
+	switch {
+	case n < karatsubaMin:
+		return 0
+	case n < toom3Min:
+		h := (n + 1) / 2
+		return 4*h - 1 + mulScratchSize(h)
+	default:
+		t := (n + 2) / 3
+		return 6*t + 3*(2*t-1) + mulScratchSize(t)
+	}
+}
+
+// mulBalanced sets dst[:2n-1] to a*b, where len(a) == len(b) == n.  The
+// operands need not be reduced.
+func mulBalanced(field *GF, dst, a, b, scratch []byte) {
+	n := len(a)
+	dst = dst[:2*n-1]
+	switch {
+	case n < karatsubaMin:
+		for i := range dst {
+			dst[i] = 0
+		}
+		mulSchoolbook(field, dst, a, b)
+	case n < toom3Min:
+		mulKaratsuba(field, dst, a, b, scratch)
+	default:
+		mulToom3(field, dst, a, b, scratch)
+	}
+}
+
+// mulSchoolbook adds a*b into dst, which must have room for
+// len(a)+len(b)-1 coefficients.  It is fastest when b is the shorter operand,
+// since the logarithms of b's coefficients are looked up once, in a buffer
+// on the stack.
+func mulSchoolbook(field *GF, dst, a, b []byte) {
+	var buf [karatsubaMin]uint
+	var logs []uint
+	if len(b) <= len(buf) {
+		logs = buf[:len(b)]
+	} else {
+		logs = make([]uint, len(b))
+	}
+	for j, bj := range b {
+		logs[j] = uint(field.log[bj])
+	}
+	for i, ai := range a {
+		if ai == 0 {
+			continue
+		}
+		logai := uint(field.log[ai])
+		out := dst[i : i+len(b)]
+		for j, bj := range b {
+			if bj != 0 {
+				out[j] ^= field.exp[logai+logs[j]]
+			}
+		}
+	}
+}
+
+// mulKaratsuba computes a*b by splitting each operand into halves at x**h:
+//
+//	(a0 + a1*x**h)(b0 + b1*x**h) = z0 + (z1 - z0 - z2)*x**h + z2*x**(2h)
+//
+// where z0 = a0*b0, z2 = a1*b1 and z1 = (a0 + a1)(b0 + b1), for three
+// half-size multiplications instead of four.  Subtraction is addition in
+// characteristic 2.
+func mulKaratsuba(field *GF, dst, a, b, scratch []byte) {
+	n := len(a)
+	h := (n + 1) / 2
+	l := n - h
+
+	sa, scratch := scratch[:h], scratch[h:]
+	sb, scratch := scratch[:h], scratch[h:]
+	z1, scratch := scratch[:2*h-1], scratch[2*h-1:]
+	copy(sa, a[:h])
+	copy(sb, b[:h])
+	for i := 0; i < l; i++ {
+		sa[i] ^= a[h+i]
+		sb[i] ^= b[h+i]
+	}
+	mulBalanced(field, z1, sa, sb, scratch)
+
+	z0 := dst[:2*h-1]
+	z2 := dst[2*h : 2*h+2*l-1]
+	mulBalanced(field, z0, a[:h], b[:h], scratch)
+	dst[2*h-1] = 0
+	mulBalanced(field, z2, a[h:], b[h:], scratch)
+
+	for i, v := range z0 {
+		z1[i] ^= v
+	}
+	for i, v := range z2 {
+		z1[i] ^= v
+	}
+	for i, v := range z1 {
+		dst[h+i] ^= v
+	}
+}
+
+// mulToom3 computes a*b by splitting each operand into thirds at y = x**t,
+// so that a = a0 + a1*y + a2*y**2, and evaluating the product
+// c(y) = c0 + c1*y + ... + c4*y**4 at the five points 0, 1, ω, ω + 1 and ∞,
+// where ω is the field element 2.  That takes five third-size
+// multiplications instead of nine.  Then c0 = a0*b0 and c4 = a2*b2 are read
+// off directly, and c1, c2 and c3 are recovered by solving a fixed 3x3
+// linear system whose inverse is computed once per call.
+func mulToom3(field *GF, dst, a, b, scratch []byte) {
+	n := len(a)
+	t := (n + 2) / 3
+	l := n - 2*t
+	w := byte(2)
+	points := [3]byte{1, w, w ^ 1}
+
+	// Evaluate each operand at 1, ω and ω+1.
+	var ea, eb [3][]byte
+	for p := range points {
+		ea[p], scratch = scratch[:t], scratch[t:]
+		eb[p], scratch = scratch[:t], scratch[t:]
+	}
+	for p, x := range points {
+		x2 := field.Mul(x, x)
+		toomEvaluate(field, ea[p], a, t, l, x, x2)
+		toomEvaluate(field, eb[p], b, t, l, x, x2)
+	}
+	var r [3][]byte
+	for p := range points {
+		r[p], scratch = scratch[:2*t-1], scratch[2*t-1:]
+		mulBalanced(field, r[p], ea[p], eb[p], scratch)
+	}
+
+	c0 := dst[:2*t-1]
+	c4 := dst[4*t : 4*t+2*l-1]
+	mulBalanced(field, c0, a[:t], b[:t], scratch)
+	for i := 2*t - 1; i < 4*t; i++ {
+		dst[i] = 0
+	}
+	mulBalanced(field, c4, a[2*t:], b[2*t:], scratch)
+
+	// r[p] - c0 - x**4*c4 = x*c1 + x**2*c2 + x**3*c3 for each point x.
+	var m [3][3]byte
+	for p, x := range points {
+		x4 := field.Mul(field.Mul(x, x), field.Mul(x, x))
+		addScaled(field, r[p], c0, 1)
+		addScaled(field, r[p], c4, x4)
+		pow := x
+		for j := range m[p] {
+			m[p][j] = pow
+			pow = field.Mul(pow, x)
+		}
+	}
+	inv := invert3(field, m)
+
+	// c_{i+1} = ∑_p inv[i][p] * r[p], accumulated directly into place.
+	for i := range inv {
+		out := dst[(i+1)*t:]
+		for p := range r {
+			addScaled(field, out[:2*t-1], r[p], inv[i][p])
+		}
+	}
+}
+
+// toomEvaluate sets dst to a0 + x*a1 + x2*a2, where a is split into pieces
+// of length t, t and l.
+func toomEvaluate(field *GF, dst, a []byte, t, l int, x, x2 byte) {
+	copy(dst, a[:t])
+	addScaled(field, dst, a[t:2*t], x)
+	addScaled(field, dst[:l], a[2*t:], x2)
+}
+
+// addScaled adds s*src into dst, which must be at least as long as src.
+func addScaled(field *GF, dst, src []byte, s byte) {
+	if s == 0 {
+		return
+	}
+	if s == 1 {
+		for i, v := range src {
+			dst[i] ^= v
+		}
+		return
+	}
+	logs := uint(field.log[s])
+	for i, v := range src {
+		if v != 0 {
+			dst[i] ^= field.exp[logs+uint(field.log[v])]
+		}
+	}
+}
+
+// invert3 returns the inverse of the non-singular 3x3 matrix m, using the
+// adjugate.  In characteristic 2 the cofactor signs can be ignored.
+func invert3(field *GF, m [3][3]byte) [3][3]byte {
+	mul := field.Mul
+	var adj [3][3]byte
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			r0, r1 := (j+1)%3, (j+2)%3
+			c0, c1 := (i+1)%3, (i+2)%3
+			adj[i][j] = mul(m[r0][c0], m[r1][c1]) ^ mul(m[r0][c1], m[r1][c0])
+		}
+	}
+	det := mul(m[0][0], adj[0][0]) ^ mul(m[0][1], adj[1][0]) ^ mul(m[0][2], adj[2][0])
+	inv := field.Inv(det)
+	for i := range adj {
+		for j := range adj[i] {
+			adj[i][j] = mul(adj[i][j], inv)
+		}
+	}
+	return adj
+}
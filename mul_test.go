@@ -0,0 +1,131 @@
+package galoisfield
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func TestPolyMul(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		q := int(field.Size())
+		for _, n := range []int{1, 2, 31, 32, 33, 63, 100, 383, 384, 385, 1000, 1500} {
+			for _, m := range []int{1, 7, n / 2, n - 1, n, 3*n + 5} {
+				if m <= 0 {
+					continue
+				}
+				a := randomCoefficients(prng, q, n)
+				b := randomCoefficients(prng, q, m)
+				expected := make([]byte, n+m-1)
+				mulSchoolbook(field, expected, a, b)
+				actual := polyMul(field, a, b)
+				if !equalBytes(actual, reduce(expected)) {
+					t.Errorf("%v: polyMul of lengths %d and %d disagrees with schoolbook",
+						field, n, m)
+				}
+			}
+		}
+	}
+}
+
+func TestPolynomial_axioms_large(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range []*GF{Poly210_g2, Default} {
+		q := int(field.Size())
+		zero := NewPolynomial(field)
+		one := NewPolynomial(field, 1)
+		for _, deg := range []int{40, 400, 1200} {
+			for trial := 0; trial < 4; trial++ {
+				checkMulAxioms(
+					t,
+					NewPolynomial(field, randomCoefficients(prng, q, deg+1)...),
+					NewPolynomial(field, randomCoefficients(prng, q, deg+1-prng.Intn(deg/2))...),
+					NewPolynomial(field, randomCoefficients(prng, q, deg+1-prng.Intn(deg/2))...),
+					zero, one)
+			}
+		}
+	}
+}
+
+func TestInvert3(t *testing.T) {
+	type testrow struct {
+		field *GF
+		m     [3][3]byte
+	}
+	identity := [3][3]byte{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+	rows := []testrow{
+		testrow{Default, identity},
+		testrow{Default, [3][3]byte{{0, 0, 5}, {7, 0, 0}, {0, 9, 0}}},
+		// The Vandermonde matrix of 1, x, x+1 in GF(4).
+		testrow{Poly210_g2, [3][3]byte{{1, 1, 1}, {1, 2, 3}, {1, 3, 2}}},
+	}
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		for trial := 0; trial < 32; trial++ {
+			var m [3][3]byte
+			for i := range m {
+				for j := range m[i] {
+					m[i][j] = byte(prng.Intn(int(field.Size())))
+				}
+			}
+			if det3(field, m) != 0 {
+				rows = append(rows, testrow{field, m})
+			}
+		}
+	}
+	for idx, row := range rows {
+		field, m := row.field, row.m
+		inv := invert3(field, m)
+		var product [3][3]byte
+		for i := range product {
+			for j := range product[i] {
+				for k := range m[i] {
+					product[i][j] ^= field.Mul(m[i][k], inv[k][j])
+				}
+			}
+		}
+		if product != identity {
+			t.Errorf("[%d] %v: expected m*invert3(m) = I for %v, got %v", idx, field, m, product)
+		}
+	}
+}
+
+// det3 returns the determinant of m by the rule of Sarrus.
+func det3(field *GF, m [3][3]byte) byte {
+	return field.Mul(m[0][0], field.Mul(m[1][1], m[2][2])) ^
+		field.Mul(m[0][1], field.Mul(m[1][2], m[2][0])) ^
+		field.Mul(m[0][2], field.Mul(m[1][0], m[2][1])) ^
+		field.Mul(m[0][2], field.Mul(m[1][1], m[2][0])) ^
+		field.Mul(m[0][0], field.Mul(m[1][2], m[2][1])) ^
+		field.Mul(m[0][1], field.Mul(m[1][0], m[2][2]))
+}
+
+func randomCoefficients(prng *rand.Rand, q, n int) []byte {
+	p := make([]byte, n)
+	for i := range p {
+		p[i] = byte(prng.Intn(q))
+	}
+	if n > 0 && p[n-1] == 0 {
+		p[n-1] = 1
+	}
+	return p
+}
+
+func BenchmarkPolyMul(b *testing.B) {
+	prng := rand.New(rand.NewSource(42))
+	for _, n := range []int{16, 32, 64, 128, 256, 512, 1024, 4096} {
+		x := randomCoefficients(prng, 256, n)
+		y := randomCoefficients(prng, 256, n)
+		b.Run(fmt.Sprintf("adaptive_%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_ = polyMul(Default, x, y)
+			}
+		})
+		b.Run(fmt.Sprintf("schoolbook_%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				mulSchoolbook(Default, make([]byte, 2*n-1), x, y)
+			}
+		})
+	}
+}
@@ -0,0 +1,567 @@
+package galoisfield
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+var (
+	ErrVariableCount = errors.New("number of variables does not match")
+	ErrNotUnivariate = errors.New("polynomial involves more than one variable")
+)
+
+// MonomialOrder selects a total order on monomials, used to sort the terms of
+// a MultiPolynomial and to pick its leading term.
+type MonomialOrder uint8
+
+const (
+	// Lex compares exponents of x0, then x1, and so on.
+	Lex MonomialOrder = iota
+
+	// GrLex compares total degrees, then breaks ties as Lex.
+	GrLex
+
+	// GrevLex compares total degrees, then breaks ties in favour of the
+	// monomial with the smaller exponent of the last variable in which
+	// the two differ.
+	GrevLex
+)
+
+// String returns the conventional name of the monomial order.
+func (o MonomialOrder) String() string {
+	switch o {
+	case Lex:
+		return "lex"
+	case GrLex:
+		return "grlex"
+	case GrevLex:
+		return "grevlex"
+	}
+	return "MonomialOrder(" + strconv.Itoa(int(o)) + ")"
+}
+
+// compare returns -1, 0 or +1 as the monomial a is less than, equal to, or
+// greater than the monomial b.
+func (o MonomialOrder) compare(a, b []uint) int {
+	if o != Lex {
+		if da, db := totalDegree(a), totalDegree(b); da != db {
+			if da < db {
+				return -1
+			}
+			return 1
+		}
+	}
+	if o == GrevLex {
+		for i := len(a) - 1; i >= 0; i-- {
+			if a[i] != b[i] {
+				if a[i] > b[i] {
+					return -1
+				}
+				return 1
+			}
+		}
+		return 0
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// MultiTerm is a single term c * x0**e0 * x1**e1 * ... of a multivariate
+// polynomial.
+type MultiTerm struct {
+	Exponents   []uint
+	Coefficient byte
+}
+
+// MultiPolynomial implements polynomials in several variables x0, x1, ...,
+// with coefficients drawn from a Galois field.  The non-zero terms are kept
+// sorted in decreasing order under a MonomialOrder, so the first term is the
+// leading term.
+//
+// Like Polynomial, MultiPolynomial is an immutable value type.  Operations
+// that combine polynomials panic with ErrIncompatibleFields or
+// ErrVariableCount if the operands differ in field or number of variables;
+// the result takes the monomial order of the first operand.
+type MultiPolynomial struct {
+	field *GF
+	nvars int
+	order MonomialOrder
+	terms []MultiTerm // decreasing, with non-zero coefficients
+}
+
+// NewMultiPolynomial returns a new polynomial in nvars variables with the
+// given terms, in any order.  Terms with equal exponents are added together,
+// and zero terms are dropped.  The exponents are copied.  If field is nil,
+// Default is used.  It panics with ErrVariableCount if a term does not have
+// exactly nvars exponents.
+func NewMultiPolynomial(field *GF, nvars int, order MonomialOrder, terms ...MultiTerm) MultiPolynomial {
+	if field == nil {
+		field = Default
+	}
+	copied := make([]MultiTerm, len(terms))
+	for i, t := range terms {
+		if len(t.Exponents) != nvars {
+			panic(ErrVariableCount)
+		}
+		copied[i] = MultiTerm{append([]uint(nil), t.Exponents...), t.Coefficient}
+	}
+	return newMultiPolynomial(field, nvars, order, copied)
+}
+
+// newMultiPolynomial sorts and combines terms, which it takes ownership of.
+func newMultiPolynomial(field *GF, nvars int, order MonomialOrder, terms []MultiTerm) MultiPolynomial {
+	sort.Slice(terms, func(i, j int) bool {
+		return order.compare(terms[i].Exponents, terms[j].Exponents) > 0
+	})
+	out := terms[:0]
+	for _, t := range terms {
+		if n := len(out); n > 0 && order.compare(out[n-1].Exponents, t.Exponents) == 0 {
+			out[n-1].Coefficient ^= t.Coefficient
+			if out[n-1].Coefficient == 0 {
+				out = out[:n-1]
+			}
+			continue
+		}
+		if t.Coefficient != 0 {
+			out = append(out, t)
+		}
+	}
+	if len(out) == 0 {
+		out = nil
+	}
+	return MultiPolynomial{field, nvars, order, out}
+}
+
+// MultiVariable returns the polynomial xi in nvars variables.  It panics with
+// ErrVariableCount if i is not less than nvars.
+func MultiVariable(field *GF, nvars int, order MonomialOrder, i int) MultiPolynomial {
+	if i < 0 || i >= nvars {
+		panic(ErrVariableCount)
+	}
+	exponents := make([]uint, nvars)
+	exponents[i] = 1
+	return NewMultiPolynomial(field, nvars, order, MultiTerm{exponents, 1})
+}
+
+// Multivariate returns this polynomial as a polynomial in the variable xi of
+// nvars variables.  It panics with ErrVariableCount if i is not less than
+// nvars.
+func (a Polynomial) Multivariate(nvars int, order MonomialOrder, i int) MultiPolynomial {
+	if i < 0 || i >= nvars {
+		panic(ErrVariableCount)
+	}
+	terms := make([]MultiTerm, 0, len(a.coefficients))
+	for d, c := range a.coefficients {
+		if c != 0 {
+			exponents := make([]uint, nvars)
+			exponents[i] = uint(d)
+			terms = append(terms, MultiTerm{exponents, c})
+		}
+	}
+	return newMultiPolynomial(a.field, nvars, order, terms)
+}
+
+// Univariate returns this polynomial as a polynomial in xi alone.  It panics
+// with ErrNotUnivariate if any other variable appears.
+func (p MultiPolynomial) Univariate(i int) Polynomial {
+	if i < 0 || i >= p.nvars {
+		panic(ErrVariableCount)
+	}
+	var coefficients []byte
+	for _, t := range p.terms {
+		for j, e := range t.Exponents {
+			if j != i && e != 0 {
+				panic(ErrNotUnivariate)
+			}
+		}
+		d := t.Exponents[i]
+		if uint(len(coefficients)) <= d {
+			coefficients = expand(int(d)+1, coefficients)
+		}
+		coefficients[d] = t.Coefficient
+	}
+	return wrapPolynomial(p.field, coefficients)
+}
+
+// Field returns the Galois field from which this polynomial's coefficients
+// are drawn.
+func (p MultiPolynomial) Field() *GF { return p.field }
+
+// NumVariables returns the number of variables of this polynomial.
+func (p MultiPolynomial) NumVariables() int { return p.nvars }
+
+// Order returns the monomial order by which this polynomial's terms are
+// sorted.
+func (p MultiPolynomial) Order() MonomialOrder { return p.order }
+
+// WithOrder returns the same polynomial with its terms sorted by order.
+func (p MultiPolynomial) WithOrder(order MonomialOrder) MultiPolynomial {
+	return newMultiPolynomial(p.field, p.nvars, order, p.copyTerms())
+}
+
+// IsZero returns true iff this polynomial has no terms.
+func (p MultiPolynomial) IsZero() bool { return len(p.terms) == 0 }
+
+// Degree returns the total degree of this polynomial, with the convention
+// that the polynomial of zero terms has degree 0.
+func (p MultiPolynomial) Degree() uint {
+	var max uint
+	for _, t := range p.terms {
+		if d := totalDegree(t.Exponents); d > max {
+			max = d
+		}
+	}
+	return max
+}
+
+// DegreeIn returns the highest exponent of xi in this polynomial.  It panics
+// with ErrVariableCount if there is no variable xi.
+func (p MultiPolynomial) DegreeIn(i int) uint {
+	if i < 0 || i >= p.nvars {
+		panic(ErrVariableCount)
+	}
+	var max uint
+	for _, t := range p.terms {
+		if t.Exponents[i] > max {
+			max = t.Exponents[i]
+		}
+	}
+	return max
+}
+
+// Terms returns the non-zero terms of this polynomial, leading term first.
+// The result is a copy.
+func (p MultiPolynomial) Terms() []MultiTerm { return p.copyTerms() }
+
+// LeadingTerm returns the greatest term of this polynomial under its
+// monomial order, or a zero term if the polynomial is zero.
+func (p MultiPolynomial) LeadingTerm() MultiTerm {
+	if p.IsZero() {
+		return MultiTerm{make([]uint, p.nvars), 0}
+	}
+	t := p.terms[0]
+	return MultiTerm{append([]uint(nil), t.Exponents...), t.Coefficient}
+}
+
+// Coefficient returns the coefficient of the monomial with the given
+// exponents.  It panics with ErrVariableCount if the number of exponents
+// differs from the number of variables.
+func (p MultiPolynomial) Coefficient(exponents ...uint) byte {
+	if len(exponents) != p.nvars {
+		panic(ErrVariableCount)
+	}
+	j := sort.Search(len(p.terms), func(j int) bool {
+		return p.order.compare(p.terms[j].Exponents, exponents) <= 0
+	})
+	if j < len(p.terms) && p.order.compare(p.terms[j].Exponents, exponents) == 0 {
+		return p.terms[j].Coefficient
+	}
+	return 0
+}
+
+// Scale returns the product of this polynomial with the scalar s.
+func (p MultiPolynomial) Scale(s byte) MultiPolynomial {
+	if s == 0 {
+		return MultiPolynomial{p.field, p.nvars, p.order, nil}
+	}
+	terms := p.copyTerms()
+	for i := range terms {
+		terms[i].Coefficient = p.field.Mul(terms[i].Coefficient, s)
+	}
+	return MultiPolynomial{p.field, p.nvars, p.order, terms}
+}
+
+// Add returns the sum of one or more polynomials.
+func (first MultiPolynomial) Add(rest ...MultiPolynomial) MultiPolynomial {
+	terms := first.copyTerms()
+	for _, next := range rest {
+		first.check(next)
+		terms = append(terms, next.copyTerms()...)
+	}
+	return newMultiPolynomial(first.field, first.nvars, first.order, terms)
+}
+
+// Mul returns the product of one or more polynomials.
+func (first MultiPolynomial) Mul(rest ...MultiPolynomial) MultiPolynomial {
+	field := first.field
+	prod := first
+	for _, next := range rest {
+		first.check(next)
+		terms := make([]MultiTerm, 0, len(prod.terms)*len(next.terms))
+		for _, a := range prod.terms {
+			for _, b := range next.terms {
+				exponents := make([]uint, first.nvars)
+				for i := range exponents {
+					exponents[i] = a.Exponents[i] + b.Exponents[i]
+				}
+				terms = append(terms, MultiTerm{exponents, field.Mul(a.Coefficient, b.Coefficient)})
+			}
+		}
+		prod = newMultiPolynomial(field, first.nvars, first.order, terms)
+	}
+	return prod
+}
+
+// Pow returns p**e.  By convention, p**0 = 1 even if p is zero.
+//
+// As for Polynomial.Pow, squaring is the Frobenius map: it squares each
+// coefficient and doubles each exponent, with no cross terms.
+func (p MultiPolynomial) Pow(e uint) MultiPolynomial {
+	result := NewMultiPolynomial(p.field, p.nvars, p.order, MultiTerm{make([]uint, p.nvars), 1})
+	for bit := highBit(e); bit > 0; bit >>= 1 {
+		terms := result.copyTerms()
+		for i := range terms {
+			terms[i].Coefficient = p.field.Mul(terms[i].Coefficient, terms[i].Coefficient)
+			for j := range terms[i].Exponents {
+				terms[i].Exponents[j] *= 2
+			}
+		}
+		result = MultiPolynomial{p.field, p.nvars, p.order, terms}
+		if e&bit != 0 {
+			result = result.Mul(p)
+		}
+	}
+	return result
+}
+
+// Evaluate substitutes the given values for x0, x1, ... and returns the
+// resulting value.  It panics with ErrVariableCount if the number of values
+// differs from the number of variables.
+func (p MultiPolynomial) Evaluate(point ...byte) byte {
+	if len(point) != p.nvars {
+		panic(ErrVariableCount)
+	}
+	field := p.field
+	var sum byte
+	for _, t := range p.terms {
+		sum ^= field.Mul(t.Coefficient, monomialValue(field, t.Exponents, point))
+	}
+	return sum
+}
+
+// PartialEvaluate substitutes the value x for the variable xi, and returns
+// the resulting polynomial, in which xi no longer appears.  The number of
+// variables is unchanged.
+func (p MultiPolynomial) PartialEvaluate(i int, x byte) MultiPolynomial {
+	if i < 0 || i >= p.nvars {
+		panic(ErrVariableCount)
+	}
+	field := p.field
+	terms := p.copyTerms()
+	for j := range terms {
+		t := &terms[j]
+		t.Coefficient = field.Mul(t.Coefficient, powByte(field, x, t.Exponents[i]))
+		t.Exponents[i] = 0
+	}
+	return newMultiPolynomial(field, p.nvars, p.order, terms)
+}
+
+// Substitute replaces the variable xi by the polynomial g, and returns the
+// resulting polynomial.  It panics with ErrIncompatibleFields or
+// ErrVariableCount if g differs from p in field or number of variables.
+func (p MultiPolynomial) Substitute(i int, g MultiPolynomial) MultiPolynomial {
+	p.check(g)
+	if i < 0 || i >= p.nvars {
+		panic(ErrVariableCount)
+	}
+	// Group the terms by their exponent of xi, so that each power of g is
+	// computed, and multiplied through, only once.
+	groups := make(map[uint][]MultiTerm)
+	for _, t := range p.copyTerms() {
+		e := t.Exponents[i]
+		t.Exponents[i] = 0
+		groups[e] = append(groups[e], t)
+	}
+	result := MultiPolynomial{p.field, p.nvars, p.order, nil}
+	for e, terms := range groups {
+		cofactor := newMultiPolynomial(p.field, p.nvars, p.order, terms)
+		result = result.Add(cofactor.Mul(g.Pow(e)))
+	}
+	return result
+}
+
+// GoString returns a Go-syntax representation of this polynomial.
+func (p MultiPolynomial) GoString() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "NewMultiPolynomial(%s, %d, %s", p.field.GoString(), p.nvars, p.order.goString())
+	for _, t := range p.terms {
+		buf.WriteString(", MultiTerm{[]uint{")
+		for j, e := range t.Exponents {
+			if j > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(strconv.FormatUint(uint64(e), 10))
+		}
+		buf.WriteString("}, ")
+		buf.WriteString(strconv.Itoa(int(t.Coefficient)))
+		buf.WriteByte('}')
+	}
+	buf.WriteByte(')')
+	return buf.String()
+}
+
+func (o MonomialOrder) goString() string {
+	switch o {
+	case Lex:
+		return "Lex"
+	case GrLex:
+		return "GrLex"
+	case GrevLex:
+		return "GrevLex"
+	}
+	return "MonomialOrder(" + strconv.Itoa(int(o)) + ")"
+}
+
+// String returns a human-readable algebraic representation of this
+// polynomial, leading term first, in the same format as Polynomial.String.
+// The variables are named x, y and z if there are at most three of them, or
+// x0, x1, ... otherwise.
+func (p MultiPolynomial) String() string {
+	if p.IsZero() {
+		return "0"
+	}
+	var buf bytes.Buffer
+	for _, t := range p.terms {
+		if buf.Len() > 0 {
+			buf.WriteString(" + ")
+		}
+		constant := totalDegree(t.Exponents) == 0
+		if t.Coefficient > 1 || constant {
+			fmt.Fprintf(&buf, "%d", t.Coefficient)
+		}
+		for j, e := range t.Exponents {
+			if e == 0 {
+				continue
+			}
+			buf.WriteString(p.variableName(j))
+			if e > 1 {
+				fmt.Fprintf(&buf, "^%d", e)
+			}
+		}
+	}
+	return buf.String()
+}
+
+func (p MultiPolynomial) variableName(i int) string {
+	if p.nvars <= 3 {
+		return string("xyz"[i])
+	}
+	return "x" + strconv.Itoa(i)
+}
+
+// Compare defines a total order for polynomials: -1 if a < b, 0 if a == b,
+// +1 if a > b.  Polynomials are ordered by field and number of variables,
+// then term by term from the leading term down, comparing monomials under
+// a's order and then coefficients.  Two polynomials that are equal but
+// sorted by different orders compare equal.
+func (a MultiPolynomial) Compare(b MultiPolynomial) int {
+	if cmp := a.field.Compare(b.field); cmp != 0 {
+		return cmp
+	}
+	if a.nvars != b.nvars {
+		if a.nvars < b.nvars {
+			return -1
+		}
+		return 1
+	}
+	if a.order != b.order {
+		b = b.WithOrder(a.order)
+	}
+	for i := 0; i < len(a.terms) && i < len(b.terms); i++ {
+		if cmp := a.order.compare(a.terms[i].Exponents, b.terms[i].Exponents); cmp != 0 {
+			return cmp
+		}
+		if ca, cb := a.terms[i].Coefficient, b.terms[i].Coefficient; ca != cb {
+			if ca < cb {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case len(a.terms) < len(b.terms):
+		return -1
+	case len(a.terms) > len(b.terms):
+		return 1
+	}
+	return 0
+}
+
+// Equal returns true iff a == b.
+func (a MultiPolynomial) Equal(b MultiPolynomial) bool {
+	return a.Compare(b) == 0
+}
+
+// Less returns true iff a < b.
+func (a MultiPolynomial) Less(b MultiPolynomial) bool {
+	return a.Compare(b) < 0
+}
+
+func (p MultiPolynomial) check(q MultiPolynomial) {
+	if p.field != q.field {
+		panic(ErrIncompatibleFields)
+	}
+	if p.nvars != q.nvars {
+		panic(ErrVariableCount)
+	}
+}
+
+// copyTerms returns a deep copy of the terms, with the exponents of all terms
+// sharing one backing array.
+func (p MultiPolynomial) copyTerms() []MultiTerm {
+	if p.IsZero() {
+		return nil
+	}
+	exponents := make([]uint, len(p.terms)*p.nvars)
+	terms := make([]MultiTerm, len(p.terms))
+	for i, t := range p.terms {
+		e := exponents[i*p.nvars : (i+1)*p.nvars : (i+1)*p.nvars]
+		copy(e, t.Exponents)
+		terms[i] = MultiTerm{e, t.Coefficient}
+	}
+	return terms
+}
+
+// monomialValue returns the product of point[i]**exponents[i].
+func monomialValue(field *GF, exponents []uint, point []byte) byte {
+	var log uint64
+	for i, e := range exponents {
+		if e == 0 {
+			continue
+		}
+		if point[i] == 0 {
+			return 0
+		}
+		log += uint64(field.log[point[i]]) * uint64(e%field.m)
+	}
+	return field.exp[log%uint64(field.m)]
+}
+
+// powByte returns x**e, with the convention that 0**0 = 1.
+func powByte(field *GF, x byte, e uint) byte {
+	if e == 0 {
+		return 1
+	}
+	if x == 0 {
+		return 0
+	}
+	return field.exp[uint64(field.log[x])*uint64(e%field.m)%uint64(field.m)]
+}
+
+func totalDegree(exponents []uint) uint {
+	var d uint
+	for _, e := range exponents {
+		d += e
+	}
+	return d
+}
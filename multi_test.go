@@ -0,0 +1,215 @@
+package galoisfield
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestMultiPolynomial_String(t *testing.T) {
+	type testrow struct {
+		input    MultiPolynomial
+		expected string
+	}
+	for idx, row := range []testrow{
+		testrow{NewMultiPolynomial(nil, 2, Lex), "0"},
+		testrow{NewMultiPolynomial(nil, 2, Lex, MultiTerm{[]uint{0, 0}, 7}), "7"},
+		testrow{NewMultiPolynomial(nil, 2, GrevLex,
+			MultiTerm{[]uint{0, 0}, 1},
+			MultiTerm{[]uint{0, 1}, 3},
+			MultiTerm{[]uint{2, 1}, 1}), "x^2y + 3y + 1"},
+		testrow{NewMultiPolynomial(nil, 3, Lex,
+			MultiTerm{[]uint{0, 0, 1}, 1},
+			MultiTerm{[]uint{0, 1, 0}, 1},
+			MultiTerm{[]uint{1, 0, 0}, 1}), "x + y + z"},
+		testrow{NewMultiPolynomial(nil, 4, Lex,
+			MultiTerm{[]uint{0, 0, 0, 5}, 2},
+			MultiTerm{[]uint{1, 0, 2, 0}, 1}), "x0x2^2 + 2x3^5"},
+		testrow{NewMultiPolynomial(nil, 2, Lex,
+			MultiTerm{[]uint{1, 1}, 5},
+			MultiTerm{[]uint{1, 1}, 5}), "0"},
+	} {
+		if actual := row.input.String(); actual != row.expected {
+			t.Errorf("[%d] expected %q, got %q", idx, row.expected, actual)
+		}
+	}
+}
+
+func TestMultiPolynomial_GoString(t *testing.T) {
+	p := NewMultiPolynomial(nil, 2, GrevLex, MultiTerm{[]uint{1, 2}, 3})
+	expected := "NewMultiPolynomial(" + Default.GoString() + ", 2, GrevLex, MultiTerm{[]uint{1, 2}, 3})"
+	if actual := p.GoString(); actual != expected {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+}
+
+func TestMonomialOrder(t *testing.T) {
+	// The example of Cox, Little & O'Shea: x*y**5*z**2 against x**4*y*z**3.
+	a := MultiTerm{[]uint{1, 5, 2}, 1}
+	b := MultiTerm{[]uint{4, 1, 3}, 1}
+	// And x*y**2 against x**2, where the total degree decides.
+	c := MultiTerm{[]uint{1, 2, 0}, 1}
+	d := MultiTerm{[]uint{2, 0, 0}, 1}
+	type testrow struct {
+		order    MonomialOrder
+		expected [2][]uint
+	}
+	for _, row := range []testrow{
+		testrow{Lex, [2][]uint{b.Exponents, d.Exponents}},
+		testrow{GrLex, [2][]uint{b.Exponents, c.Exponents}},
+		testrow{GrevLex, [2][]uint{a.Exponents, c.Exponents}},
+	} {
+		for i, pair := range [][2]MultiTerm{{a, b}, {c, d}} {
+			p := NewMultiPolynomial(nil, 3, row.order, pair[0], pair[1])
+			lead := p.LeadingTerm().Exponents
+			if row.order.compare(lead, row.expected[i]) != 0 {
+				t.Errorf("%v: expected leading monomial %v of (%v), got %v",
+					row.order, row.expected[i], p, lead)
+			}
+		}
+	}
+}
+
+func TestMultiPolynomial_arithmetic(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		q := int(field.Size())
+		for _, order := range []MonomialOrder{Lex, GrLex, GrevLex} {
+			for trial := 0; trial < 8; trial++ {
+				nvars := 1 + prng.Intn(4)
+				a := randomMultiPolynomial(prng, field, nvars, order, 6, 4)
+				b := randomMultiPolynomial(prng, field, nvars, order, 6, 4)
+				g := randomMultiPolynomial(prng, field, nvars, order, 3, 2)
+				i := prng.Intn(nvars)
+				sum, prod, cube := a.Add(b), a.Mul(b), a.Pow(3)
+				sub := a.Substitute(i, g)
+				xi := byte(prng.Intn(q))
+				partial := a.PartialEvaluate(i, xi)
+				if partial.DegreeIn(i) != 0 {
+					t.Errorf("%v: x%d remains in (%v)", field, i, partial)
+				}
+				for k := 0; k < 8; k++ {
+					pt := make([]byte, nvars)
+					for j := range pt {
+						pt[j] = byte(prng.Intn(q))
+					}
+					va, vb := a.Evaluate(pt...), b.Evaluate(pt...)
+					if v := sum.Evaluate(pt...); v != va^vb {
+						t.Errorf("%v: (%v) + (%v) at %v: expected %d, got %d", field, a, b, pt, va^vb, v)
+					}
+					if v, e := prod.Evaluate(pt...), field.Mul(va, vb); v != e {
+						t.Errorf("%v: (%v) * (%v) at %v: expected %d, got %d", field, a, b, pt, e, v)
+					}
+					if v, e := cube.Evaluate(pt...), field.Mul(va, field.Mul(va, va)); v != e {
+						t.Errorf("%v: (%v)**3 at %v: expected %d, got %d", field, a, pt, e, v)
+					}
+					at := append([]byte(nil), pt...)
+					at[i] = g.Evaluate(pt...)
+					if v, e := sub.Evaluate(pt...), a.Evaluate(at...); v != e {
+						t.Errorf("%v: (%v) with x%d = (%v) at %v: expected %d, got %d",
+							field, a, i, g, pt, e, v)
+					}
+					at[i] = xi
+					if v, e := partial.Evaluate(pt...), a.Evaluate(at...); v != e {
+						t.Errorf("%v: (%v) with x%d = %d at %v: expected %d, got %d",
+							field, a, i, xi, pt, e, v)
+					}
+				}
+				if !a.Mul(b).Equal(b.Mul(a)) || !a.Add(b).Add(b).Equal(a) {
+					t.Errorf("%v: axioms fail for (%v), (%v)", field, a, b)
+				}
+				for _, t0 := range a.Terms() {
+					if c := a.Coefficient(t0.Exponents...); c != t0.Coefficient {
+						t.Errorf("%v: Coefficient(%v) of (%v): expected %d, got %d",
+							field, t0.Exponents, a, t0.Coefficient, c)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestMultiPolynomial_Compare(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	a := randomMultiPolynomial(prng, nil, 3, Lex, 8, 5)
+	if b := a.WithOrder(GrevLex); !a.Equal(b) || !b.Equal(a) {
+		t.Errorf("expected (%v) to equal itself under grevlex (%v)", a, b)
+	}
+	one := NewMultiPolynomial(nil, 3, Lex, MultiTerm{[]uint{0, 0, 0}, 1})
+	x := MultiVariable(nil, 3, Lex, 0)
+	y := MultiVariable(nil, 3, Lex, 1)
+	for idx, pair := range [][2]MultiPolynomial{
+		{NewMultiPolynomial(nil, 3, Lex), one},
+		{one, y},
+		{y, x},
+		{x, x.Add(one)},
+		{x, x.Scale(2)},
+		{one, NewMultiPolynomial(nil, 4, Lex)},
+	} {
+		if !pair[0].Less(pair[1]) || pair[1].Less(pair[0]) {
+			t.Errorf("[%d] expected (%v) < (%v)", idx, pair[0], pair[1])
+		}
+	}
+}
+
+func TestMultiPolynomial_univariate(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		a := NewPolynomial(field, randomCoefficients(prng, int(field.Size()), 10)...)
+		p := a.Multivariate(3, GrevLex, 1)
+		if actual := p.Univariate(1); !actual.Equal(a) {
+			t.Errorf("%v: round trip of (%v) gave (%v)", field, a, actual)
+		}
+		if p.Degree() != a.Degree() || p.DegreeIn(1) != a.Degree() || p.DegreeIn(0) != 0 {
+			t.Errorf("%v: wrong degrees for (%v)", field, p)
+		}
+		for x := 0; x < int(field.Size()); x++ {
+			if p.Evaluate(0, byte(x), 1) != a.Evaluate(byte(x)) {
+				t.Errorf("%v: (%v) disagrees with (%v) at %d", field, p, a, x)
+			}
+		}
+	}
+}
+
+func TestMultiPolynomial_panics(t *testing.T) {
+	type testrow struct {
+		fn       func()
+		expected error
+	}
+	x := MultiVariable(nil, 2, Lex, 0)
+	for idx, row := range []testrow{
+		testrow{func() {
+			NewMultiPolynomial(nil, 2, Lex, MultiTerm{[]uint{1}, 1})
+		}, ErrVariableCount},
+		testrow{func() { MultiVariable(nil, 2, Lex, 2) }, ErrVariableCount},
+		testrow{func() { x.Add(MultiVariable(nil, 3, Lex, 0)) }, ErrVariableCount},
+		testrow{func() { x.Mul(MultiVariable(Poly210_g2, 2, Lex, 0)) }, ErrIncompatibleFields},
+		testrow{func() { x.Evaluate(1) }, ErrVariableCount},
+		testrow{func() { x.Coefficient(1, 2, 3) }, ErrVariableCount},
+		testrow{func() { x.PartialEvaluate(5, 1) }, ErrVariableCount},
+		testrow{func() { x.DegreeIn(2) }, ErrVariableCount},
+		testrow{func() { NewMultiPolynomial(nil, 2, Lex).DegreeIn(-1) }, ErrVariableCount},
+		testrow{func() { x.Mul(MultiVariable(nil, 2, Lex, 1)).Univariate(0) }, ErrNotUnivariate},
+	} {
+		if e := panicValue(row.fn); e != row.expected {
+			t.Errorf("[%d] expected panic(%v), got %v", idx, row.expected, e)
+		}
+	}
+}
+
+// randomMultiPolynomial returns a polynomial with up to n terms, each of
+// total degree at most d.
+func randomMultiPolynomial(prng *rand.Rand, field *GF, nvars int, order MonomialOrder, n, d int) MultiPolynomial {
+	if field == nil {
+		field = Default
+	}
+	q := int(field.Size())
+	terms := make([]MultiTerm, prng.Intn(n+1))
+	for i := range terms {
+		exponents := make([]uint, nvars)
+		for k := prng.Intn(d + 1); k > 0; k-- {
+			exponents[prng.Intn(nvars)]++
+		}
+		terms[i] = MultiTerm{exponents, byte(1 + prng.Intn(q-1))}
+	}
+	return NewMultiPolynomial(field, nvars, order, terms...)
+}
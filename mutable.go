@@ -0,0 +1,217 @@
+package galoisfield
+
+// MutablePolynomial is a polynomial whose coefficients can be updated in
+// place.  It is meant for inner loops, such as those of decoders, where the
+// allocations made by every Polynomial operation would dominate: each
+// MutablePolynomial keeps its coefficient buffer, and the scratch space
+// used by multiplication and division, from one operation to the next.
+//
+// The methods follow the convention of math/big: the receiver holds the
+// result and is also returned, and operands may alias the receiver.  Unlike
+// Polynomial, a MutablePolynomial must not be shared between goroutines
+// without synchronization.
+//
+// The zero value is not usable; create one with NewMutablePolynomial.
+type MutablePolynomial struct {
+	field        *GF
+	coefficients []byte // reduced, but with capacity kept for reuse
+
+	// Reusable scratch space.
+	product []byte
+	scratch []byte
+	logs    []uint
+}
+
+// NewMutablePolynomial returns a new mutable polynomial with the given
+// coefficients, in little-endian order (see NewPolynomial).  The
+// coefficients are copied.  If field is nil, Default is used.
+func NewMutablePolynomial(field *GF, coefficients ...byte) *MutablePolynomial {
+	if field == nil {
+		field = Default
+	}
+	p := &MutablePolynomial{field: field}
+	return p.SetCoefficients(coefficients...)
+}
+
+// Field returns the Galois field from which this polynomial's coefficients
+// are drawn.
+func (p *MutablePolynomial) Field() *GF { return p.field }
+
+// IsZero returns true iff this polynomial has no terms.
+func (p *MutablePolynomial) IsZero() bool { return len(p.coefficients) == 0 }
+
+// Degree returns the degree of this polynomial, with the convention that the
+// polynomial of zero terms has degree 0.
+func (p *MutablePolynomial) Degree() uint {
+	if p.IsZero() {
+		return 0
+	}
+	return uint(len(p.coefficients) - 1)
+}
+
+// Coefficient returns the coefficient of the i'th term.
+func (p *MutablePolynomial) Coefficient(i uint) byte {
+	if i >= uint(len(p.coefficients)) {
+		return 0
+	}
+	return p.coefficients[i]
+}
+
+// SetCoefficient sets the coefficient of the i'th term to c, and returns p.
+func (p *MutablePolynomial) SetCoefficient(i uint, c byte) *MutablePolynomial {
+	if i >= uint(len(p.coefficients)) {
+		if c == 0 {
+			return p
+		}
+		p.resize(int(i) + 1)
+	}
+	p.coefficients[i] = c
+	p.trim()
+	return p
+}
+
+// SetCoefficients sets p to the polynomial with the given coefficients, and
+// returns p.  The coefficients are copied.
+func (p *MutablePolynomial) SetCoefficients(coefficients ...byte) *MutablePolynomial {
+	coefficients = reduce(coefficients)
+	p.coefficients = append(p.coefficients[:0], coefficients...)
+	return p
+}
+
+// Set sets p to a, and returns p.  It panics with ErrIncompatibleFields if a
+// is drawn from a different field.
+func (p *MutablePolynomial) Set(a Polynomial) *MutablePolynomial {
+	if a.field != p.field {
+		panic(ErrIncompatibleFields)
+	}
+	return p.SetCoefficients(a.coefficients...)
+}
+
+// SetZero sets p to the zero polynomial, and returns p.
+func (p *MutablePolynomial) SetZero() *MutablePolynomial {
+	p.coefficients = p.coefficients[:0]
+	return p
+}
+
+// Polynomial returns an immutable copy of p.
+func (p *MutablePolynomial) Polynomial() Polynomial {
+	return NewPolynomial(p.field, p.coefficients...)
+}
+
+// AddInto sets p to a + b, and returns p.  It panics with
+// ErrIncompatibleFields if the operands are drawn from different fields.
+func (p *MutablePolynomial) AddInto(a, b *MutablePolynomial) *MutablePolynomial {
+	p.checkFields(a, b)
+	if p == b {
+		a, b = b, a
+	}
+	if p != a {
+		p.coefficients = append(p.coefficients[:0], a.coefficients...)
+	}
+	if len(b.coefficients) > len(p.coefficients) {
+		p.resize(len(b.coefficients))
+	}
+	for i, c := range b.coefficients {
+		p.coefficients[i] ^= c
+	}
+	p.trim()
+	return p
+}
+
+// MulInto sets p to a * b, and returns p.  It panics with
+// ErrIncompatibleFields if the operands are drawn from different fields.
+func (p *MutablePolynomial) MulInto(a, b *MutablePolynomial) *MutablePolynomial {
+	p.checkFields(a, b)
+	if a.IsZero() || b.IsZero() {
+		return p.SetZero()
+	}
+	n := len(a.coefficients) + len(b.coefficients) - 1
+	if cap(p.product) < n {
+		p.product = make([]byte, n)
+	}
+	prod := p.product[:n]
+	p.scratch = mulInto(p.field, prod, a.coefficients, b.coefficients, p.scratch)
+	// The product buffer becomes the coefficients, and vice versa, so
+	// that neither needs copying even if p is one of the operands.
+	p.product, p.coefficients = p.coefficients, prod
+	p.trim()
+	return p
+}
+
+// ScaleInPlace multiplies p by the scalar s, and returns p.
+func (p *MutablePolynomial) ScaleInPlace(s byte) *MutablePolynomial {
+	if s == 0 {
+		return p.SetZero()
+	}
+	if s == 1 {
+		return p
+	}
+	logs := uint(p.field.log[s])
+	for i, c := range p.coefficients {
+		if c != 0 {
+			p.coefficients[i] = p.field.exp[logs+uint(p.field.log[c])]
+		}
+	}
+	return p
+}
+
+// ModInPlace sets p to p mod m, and returns p.  It panics with ErrDivByZero
+// if m is zero, or with ErrIncompatibleFields if m is drawn from a different
+// field.
+func (p *MutablePolynomial) ModInPlace(m *MutablePolynomial) *MutablePolynomial {
+	p.checkFields(m, m)
+	if m.IsZero() {
+		panic(ErrDivByZero)
+	}
+	if p == m {
+		return p.SetZero()
+	}
+	if len(p.coefficients) < len(m.coefficients) {
+		return p
+	}
+	p.logs = logSlice(p.field, p.logs, m.coefficients)
+	polyDivModLogs(p.field, nil, p.coefficients, m.coefficients, p.logs)
+	p.coefficients = p.coefficients[:len(m.coefficients)-1]
+	p.trim()
+	return p
+}
+
+// Evaluate substitutes for x and returns the resulting value.
+func (p *MutablePolynomial) Evaluate(x byte) byte {
+	return horner(p.field, p.coefficients, x)
+}
+
+// String returns a human-readable algebraic representation of p.
+func (p *MutablePolynomial) String() string {
+	return Polynomial{p.field, reduce(p.coefficients)}.String()
+}
+
+func (p *MutablePolynomial) checkFields(a, b *MutablePolynomial) {
+	if a.field != p.field || b.field != p.field {
+		panic(ErrIncompatibleFields)
+	}
+}
+
+// resize extends the coefficients to length n, zero-filling the new terms.
+func (p *MutablePolynomial) resize(n int) {
+	old := len(p.coefficients)
+	if cap(p.coefficients) < n {
+		grown := make([]byte, n, 2*n)
+		copy(grown, p.coefficients)
+		p.coefficients = grown
+		return
+	}
+	p.coefficients = p.coefficients[:n]
+	for i := old; i < n; i++ {
+		p.coefficients[i] = 0
+	}
+}
+
+// trim removes leading zero coefficients, keeping the buffer's capacity.
+func (p *MutablePolynomial) trim() {
+	n := len(p.coefficients)
+	for n > 0 && p.coefficients[n-1] == 0 {
+		n--
+	}
+	p.coefficients = p.coefficients[:n]
+}
@@ -0,0 +1,149 @@
+package galoisfield
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestMutablePolynomial(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		q := int(field.Size())
+		for trial := 0; trial < 32; trial++ {
+			a := NewPolynomial(field, randomCoefficients(prng, q, prng.Intn(80))...)
+			b := NewPolynomial(field, randomCoefficients(prng, q, prng.Intn(80))...)
+			m := NewPolynomial(field, randomCoefficients(prng, q, 1+prng.Intn(40))...)
+			s := byte(prng.Intn(q))
+			ma := NewMutablePolynomial(field).Set(a)
+			mb := NewMutablePolynomial(field).Set(b)
+			mm := NewMutablePolynomial(field).Set(m)
+			p := NewMutablePolynomial(field)
+
+			check := func(name string, expected Polynomial, actual *MutablePolynomial) {
+				if !actual.Polynomial().Equal(expected) {
+					t.Errorf("%v: %s of (%v), (%v): expected (%v), got (%v)",
+						field, name, a, b, expected, actual)
+				}
+				if actual.Degree() != expected.Degree() || actual.IsZero() != expected.IsZero() {
+					t.Errorf("%v: %s: expected degree %d, got %d",
+						field, name, expected.Degree(), actual.Degree())
+				}
+			}
+			check("AddInto", a.Add(b), p.AddInto(ma, mb))
+			check("MulInto", a.Mul(b), p.MulInto(ma, mb))
+			check("ModInPlace", a.Mul(b).Mod(m), p.ModInPlace(mm))
+			check("ScaleInPlace", a.Mul(b).Mod(m).Scale(s), p.ScaleInPlace(s))
+			if x := byte(prng.Intn(q)); p.Evaluate(x) != a.Mul(b).Mod(m).Scale(s).Evaluate(x) {
+				t.Errorf("%v: Evaluate(%d) disagrees with Polynomial.Evaluate", field, x)
+			}
+
+			// Aliased operands.
+			pa := NewMutablePolynomial(field).Set(a)
+			check("AddInto (p = p + b)", a.Add(b), pa.AddInto(pa, mb))
+			pa = NewMutablePolynomial(field).Set(a)
+			check("AddInto (p = b + p)", a.Add(b), pa.AddInto(mb, pa))
+			pa = NewMutablePolynomial(field).Set(a)
+			check("AddInto (p = p + p)", NewPolynomial(field), pa.AddInto(pa, pa))
+			pa = NewMutablePolynomial(field).Set(a)
+			check("MulInto (p = p * b)", a.Mul(b), pa.MulInto(pa, mb))
+			pa = NewMutablePolynomial(field).Set(a)
+			check("MulInto (p = p * p)", a.Mul(a), pa.MulInto(pa, pa))
+			check("ModInPlace (p = p mod p)", NewPolynomial(field), mm.clone().ModInPlace(mm))
+		}
+	}
+}
+
+func TestMutablePolynomial_SetCoefficient(t *testing.T) {
+	p := NewMutablePolynomial(nil, 1, 2)
+	p.SetCoefficient(5, 3)
+	if expected := NewPolynomial(nil, 1, 2, 0, 0, 0, 3); !p.Polynomial().Equal(expected) {
+		t.Errorf("expected (%v), got (%v)", expected, p)
+	}
+	p.SetCoefficient(5, 0)
+	if expected := NewPolynomial(nil, 1, 2); !p.Polynomial().Equal(expected) || p.Degree() != 1 {
+		t.Errorf("expected (%v), got (%v)", expected, p)
+	}
+	p.SetCoefficient(9, 0)
+	if p.Degree() != 1 || p.Coefficient(9) != 0 {
+		t.Errorf("expected setting a zero coefficient past the end to do nothing, got (%v)", p)
+	}
+}
+
+func TestMutablePolynomial_panics(t *testing.T) {
+	type testrow struct {
+		fn       func()
+		expected error
+	}
+	for idx, row := range []testrow{
+		testrow{func() {
+			NewMutablePolynomial(nil, 1).ModInPlace(NewMutablePolynomial(nil))
+		}, ErrDivByZero},
+		testrow{func() {
+			NewMutablePolynomial(nil, 1).AddInto(
+				NewMutablePolynomial(nil, 1),
+				NewMutablePolynomial(Poly210_g2, 1))
+		}, ErrIncompatibleFields},
+		testrow{func() {
+			NewMutablePolynomial(nil).Set(NewPolynomial(Poly210_g2, 1))
+		}, ErrIncompatibleFields},
+	} {
+		if e := panicValue(row.fn); e != row.expected {
+			t.Errorf("[%d] expected panic(%v), got %v", idx, row.expected, e)
+		}
+	}
+}
+
+func TestMutablePolynomial_allocs(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	a := NewMutablePolynomial(nil, randomCoefficients(prng, 256, 200)...)
+	b := NewMutablePolynomial(nil, randomCoefficients(prng, 256, 150)...)
+	m := NewMutablePolynomial(nil, randomCoefficients(prng, 256, 64)...)
+	p := NewMutablePolynomial(nil)
+	step := func() {
+		p.MulInto(a, b).ModInPlace(m).ScaleInPlace(7).AddInto(p, m)
+	}
+	step()
+	if allocs := testing.AllocsPerRun(100, step); allocs != 0 {
+		t.Errorf("expected no allocations once buffers are warm, got %v", allocs)
+	}
+}
+
+func TestPolynomial_defensiveCopies(t *testing.T) {
+	input := []byte{1, 2, 3}
+	p := NewPolynomial(nil, input...)
+	input[0] = 9
+	if p.Coefficient(0) != 1 {
+		t.Errorf("expected NewPolynomial to copy its input, got (%v)", p)
+	}
+	c := p.Coefficients()
+	c[1] = 9
+	if p.Coefficient(1) != 2 {
+		t.Errorf("expected Coefficients to return a copy, got (%v)", p)
+	}
+}
+
+func (p *MutablePolynomial) clone() *MutablePolynomial {
+	return NewMutablePolynomial(p.field, p.coefficients...)
+}
+
+func BenchmarkMutablePolynomial_MulMod(b *testing.B) {
+	prng := rand.New(rand.NewSource(42))
+	x := NewMutablePolynomial(nil, randomCoefficients(prng, 256, 128)...)
+	m := NewMutablePolynomial(nil, randomCoefficients(prng, 256, 129)...)
+	p := NewMutablePolynomial(nil, 1)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p.MulInto(p, x).ModInPlace(m)
+	}
+}
+
+func BenchmarkPolynomial_MulMod(b *testing.B) {
+	prng := rand.New(rand.NewSource(42))
+	x := NewPolynomial(nil, randomCoefficients(prng, 256, 128)...)
+	m := NewPolynomial(nil, randomCoefficients(prng, 256, 129)...)
+	p := NewPolynomial(nil, 1)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p = p.Mul(x).Mod(m)
+	}
+}
@@ -0,0 +1,374 @@
+package galoisfield
+
+import (
+	"bytes"
+	"strconv"
+)
+
+// PolyMatrix implements matrices whose entries are polynomials over a Galois
+// field.  They describe convolutional encoders, and their row reductions
+// solve simultaneous rational approximation problems, such as synthesizing
+// one shift register for several sequences at once.
+//
+// Like Matrix, PolyMatrix is an immutable value type, with the same panics.
+// All entries are drawn from the same field.
+type PolyMatrix struct {
+	field      *GF
+	rows, cols int
+	entries    []Polynomial // row-major
+}
+
+// NewPolyMatrix returns a new rows×cols polynomial matrix with the given
+// entries, in row-major order; missing entries are zero.  If field is nil,
+// Default is used.  It panics with ErrDimensionMismatch if there are more
+// than rows*cols entries, or with ErrIncompatibleFields if an entry is drawn
+// from a different field.
+func NewPolyMatrix(field *GF, rows, cols int, entries ...Polynomial) PolyMatrix {
+	if field == nil {
+		field = Default
+	}
+	if rows < 0 || cols < 0 || len(entries) > rows*cols {
+		panic(ErrDimensionMismatch)
+	}
+	m := newPolyMatrix(field, rows, cols)
+	for i, p := range entries {
+		if p.field != field {
+			panic(ErrIncompatibleFields)
+		}
+		m.entries[i] = p
+	}
+	return m
+}
+
+// PolyIdentity returns the n×n identity polynomial matrix.  If field is nil,
+// Default is used.
+func PolyIdentity(field *GF, n int) PolyMatrix {
+	if field == nil {
+		field = Default
+	}
+	m := newPolyMatrix(field, n, n)
+	for i := 0; i < n; i++ {
+		m.entries[i*n+i] = NewPolynomial(field, 1)
+	}
+	return m
+}
+
+// PolyMatrix returns this matrix as a polynomial matrix with constant
+// entries.
+func (m Matrix) PolyMatrix() PolyMatrix {
+	pm := newPolyMatrix(m.field, m.rows, m.cols)
+	for i, v := range m.data {
+		pm.entries[i] = NewPolynomial(m.field, v)
+	}
+	return pm
+}
+
+// Field returns the Galois field from which the coefficients of this
+// matrix's entries are drawn.
+func (m PolyMatrix) Field() *GF { return m.field }
+
+// Rows returns the number of rows.
+func (m PolyMatrix) Rows() int { return m.rows }
+
+// Cols returns the number of columns.
+func (m PolyMatrix) Cols() int { return m.cols }
+
+// At returns the entry in row i and column j.
+func (m PolyMatrix) At(i, j int) Polynomial {
+	if i < 0 || i >= m.rows || j < 0 || j >= m.cols {
+		panic(ErrDimensionMismatch)
+	}
+	return m.entries[i*m.cols+j]
+}
+
+// IsSquare returns true iff this matrix has as many rows as columns.
+func (m PolyMatrix) IsSquare() bool { return m.rows == m.cols }
+
+// Degree returns the greatest degree of the entries of this matrix.
+func (m PolyMatrix) Degree() uint {
+	var d uint
+	for _, p := range m.entries {
+		if p.Degree() > d {
+			d = p.Degree()
+		}
+	}
+	return d
+}
+
+// Add returns the sum of one or more polynomial matrices of the same shape.
+func (first PolyMatrix) Add(rest ...PolyMatrix) PolyMatrix {
+	sum := first.clone()
+	for _, next := range rest {
+		first.checkField(next)
+		if next.rows != first.rows || next.cols != first.cols {
+			panic(ErrDimensionMismatch)
+		}
+		for i, p := range next.entries {
+			sum.entries[i] = sum.entries[i].Add(p)
+		}
+	}
+	return sum
+}
+
+// Mul returns the product of one or more polynomial matrices.
+func (first PolyMatrix) Mul(rest ...PolyMatrix) PolyMatrix {
+	prod := first
+	for _, next := range rest {
+		first.checkField(next)
+		if prod.cols != next.rows {
+			panic(ErrDimensionMismatch)
+		}
+		out := newPolyMatrix(first.field, prod.rows, next.cols)
+		for i := 0; i < prod.rows; i++ {
+			for j := 0; j < next.cols; j++ {
+				sum := out.entries[i*out.cols+j]
+				for k := 0; k < prod.cols; k++ {
+					a, b := prod.entries[i*prod.cols+k], next.entries[k*next.cols+j]
+					if !a.IsZero() && !b.IsZero() {
+						sum = sum.Add(a.Mul(b))
+					}
+				}
+				out.entries[i*out.cols+j] = sum
+			}
+		}
+		prod = out
+	}
+	return prod
+}
+
+// Transpose returns the transpose of this matrix.
+func (m PolyMatrix) Transpose() PolyMatrix {
+	t := newPolyMatrix(m.field, m.cols, m.rows)
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			t.entries[j*m.rows+i] = m.entries[i*m.cols+j]
+		}
+	}
+	return t
+}
+
+// Evaluate returns the matrix of the values of the entries at x.
+func (m PolyMatrix) Evaluate(x byte) Matrix {
+	out := NewMatrix(m.field, m.rows, m.cols)
+	for i, p := range m.entries {
+		out.data[i] = p.Evaluate(x)
+	}
+	return out
+}
+
+// Determinant returns the determinant of this square matrix.  It panics with
+// ErrDimensionMismatch if the matrix is not square.
+//
+// This is Bareiss's fraction-free elimination, which stays within the
+// polynomials: each step computes 2×2 minors and divides them exactly by
+// the previous pivot, so the degrees grow no faster than those of the
+// minors.
+func (m PolyMatrix) Determinant() Polynomial {
+	if !m.IsSquare() {
+		panic(ErrDimensionMismatch)
+	}
+	n := m.rows
+	a := m.clone()
+	at := func(i, j int) *Polynomial { return &a.entries[i*n+j] }
+	prev := NewPolynomial(m.field, 1)
+	for k := 0; k < n; k++ {
+		p := k
+		for p < n && at(p, k).IsZero() {
+			p++
+		}
+		if p == n {
+			return Polynomial{m.field, nil}
+		}
+		// In characteristic 2, swapping rows does not change the sign.
+		for j := k; j < n; j++ {
+			*at(k, j), *at(p, j) = *at(p, j), *at(k, j)
+		}
+		pivot := *at(k, k)
+		for i := k + 1; i < n; i++ {
+			for j := k + 1; j < n; j++ {
+				minor := at(i, j).Mul(pivot).Add(at(i, k).Mul(*at(k, j)))
+				*at(i, j), _ = minor.DivMod(prev)
+			}
+		}
+		prev = pivot
+	}
+	if n == 0 {
+		return prev
+	}
+	return *at(n-1, n-1)
+}
+
+// RowDegrees returns the degree of each row, the greatest degree of its
+// entries, or -1 for a zero row.
+func (m PolyMatrix) RowDegrees() []int {
+	degrees := make([]int, m.rows)
+	for i := range degrees {
+		degrees[i], _ = m.leadingPosition(i)
+	}
+	return degrees
+}
+
+// LeadingPositions returns the leading position of each row, the rightmost
+// column holding an entry of the row's degree, or -1 for a zero row.
+func (m PolyMatrix) LeadingPositions() []int {
+	positions := make([]int, m.rows)
+	for i := range positions {
+		_, positions[i] = m.leadingPosition(i)
+	}
+	return positions
+}
+
+// IsWeakPopov returns true iff the leading positions of the non-zero rows
+// of this matrix are distinct.
+func (m PolyMatrix) IsWeakPopov() bool {
+	seen := make([]bool, m.cols)
+	for _, c := range m.LeadingPositions() {
+		if c < 0 {
+			continue
+		}
+		if seen[c] {
+			return false
+		}
+		seen[c] = true
+	}
+	return true
+}
+
+// WeakPopov returns a weak Popov form W of this matrix, together with the
+// unimodular matrix U such that U*m = W.  The non-zero rows of W have
+// distinct leading positions, and the rank of m is their number.  W is row
+// reduced: no basis of the same row space has smaller row degrees.
+//
+// This is the algorithm of Mulders and Storjohann: while two rows share a
+// leading position, the one of greater degree is reduced by a monomial
+// multiple of the other, which cancels its leading term.  Each reduction
+// lowers the degree of the row or moves its leading position left, so the
+// process terminates.
+func (m PolyMatrix) WeakPopov() (w, u PolyMatrix) {
+	w, u = m.clone(), PolyIdentity(m.field, m.rows)
+	degrees := w.RowDegrees()
+	positions := w.LeadingPositions()
+	for {
+		// owner maps each leading position to the first row found with it.
+		owner := make([]int, m.cols)
+		for c := range owner {
+			owner[c] = -1
+		}
+		i, j := -1, -1
+		for r, c := range positions {
+			if c < 0 {
+				continue
+			}
+			if owner[c] < 0 {
+				owner[c] = r
+				continue
+			}
+			i, j = owner[c], r
+			break
+		}
+		if i < 0 {
+			return w, u
+		}
+		if degrees[i] < degrees[j] {
+			i, j = j, i
+		}
+		// Cancel the leading term of row i with a multiple of row j.
+		c := positions[i]
+		shift := make([]byte, degrees[i]-degrees[j]+1)
+		shift[len(shift)-1] = m.field.Div(w.At(i, c).LeadingCoefficient(), w.At(j, c).LeadingCoefficient())
+		s := NewPolynomial(m.field, shift...)
+		w.addRowMultiple(i, j, s)
+		u.addRowMultiple(i, j, s)
+		degrees[i], positions[i] = w.leadingPosition(i)
+	}
+}
+
+// Equal returns true iff a == b.
+func (a PolyMatrix) Equal(b PolyMatrix) bool {
+	if a.field != b.field || a.rows != b.rows || a.cols != b.cols {
+		return false
+	}
+	for i, p := range a.entries {
+		if !p.Equal(b.entries[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// GoString returns a Go-syntax representation of this matrix.
+func (m PolyMatrix) GoString() string {
+	var buf bytes.Buffer
+	buf.WriteString("NewPolyMatrix(")
+	buf.WriteString(m.field.GoString())
+	buf.WriteString(", ")
+	buf.WriteString(strconv.Itoa(m.rows))
+	buf.WriteString(", ")
+	buf.WriteString(strconv.Itoa(m.cols))
+	for _, p := range m.entries {
+		buf.WriteString(", ")
+		buf.WriteString(p.GoString())
+	}
+	buf.WriteByte(')')
+	return buf.String()
+}
+
+// String returns a human-readable representation of this matrix, with
+// entries separated by commas and rows by semicolons, such as
+// "[x + 1, 0; 1, x]".
+func (m PolyMatrix) String() string {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i := 0; i < m.rows; i++ {
+		if i > 0 {
+			buf.WriteString("; ")
+		}
+		for j := 0; j < m.cols; j++ {
+			if j > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(m.entries[i*m.cols+j].String())
+		}
+	}
+	buf.WriteByte(']')
+	return buf.String()
+}
+
+func newPolyMatrix(field *GF, rows, cols int) PolyMatrix {
+	entries := make([]Polynomial, rows*cols)
+	for i := range entries {
+		entries[i] = Polynomial{field, nil}
+	}
+	return PolyMatrix{field, rows, cols, entries}
+}
+
+// leadingPosition returns the degree and leading position of row i, or -1,
+// -1 for a zero row.
+func (m PolyMatrix) leadingPosition(i int) (degree, position int) {
+	degree, position = -1, -1
+	for j, p := range m.entries[i*m.cols : (i+1)*m.cols] {
+		if !p.IsZero() && int(p.Degree()) >= degree {
+			degree, position = int(p.Degree()), j
+		}
+	}
+	return degree, position
+}
+
+// addRowMultiple adds s times row j to row i, in place.
+func (m PolyMatrix) addRowMultiple(i, j int, s Polynomial) {
+	for k := 0; k < m.cols; k++ {
+		if src := m.entries[j*m.cols+k]; !src.IsZero() {
+			m.entries[i*m.cols+k] = m.entries[i*m.cols+k].Add(s.Mul(src))
+		}
+	}
+}
+
+func (m PolyMatrix) clone() PolyMatrix {
+	return PolyMatrix{m.field, m.rows, m.cols, append([]Polynomial(nil), m.entries...)}
+}
+
+func (m PolyMatrix) checkField(b PolyMatrix) {
+	if m.field != b.field {
+		panic(ErrIncompatibleFields)
+	}
+}
@@ -0,0 +1,150 @@
+package galoisfield
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestPolyMatrix_String(t *testing.T) {
+	m := NewPolyMatrix(Poly210_g2, 2, 2, NewPolynomial(Poly210_g2, 1, 1), NewPolynomial(Poly210_g2),
+		NewPolynomial(Poly210_g2, 1), NewPolynomial(Poly210_g2, 0, 3))
+	if expected := "[x + 1, 0; 1, 3x]"; m.String() != expected {
+		t.Errorf("String: expected %q, got %q", expected, m.String())
+	}
+	expected := "NewPolyMatrix(Poly210_g2, 2, 2, NewPolynomial(Poly210_g2, 1, 1), " +
+		"NewPolynomial(Poly210_g2), NewPolynomial(Poly210_g2, 1), NewPolynomial(Poly210_g2, 0, 3))"
+	if m.GoString() != expected {
+		t.Errorf("GoString: expected %q, got %q", expected, m.GoString())
+	}
+	if m.Degree() != 1 || !m.Transpose().Transpose().Equal(m) || m.Transpose().Equal(m) {
+		t.Errorf("bad Degree or Transpose for %v", m)
+	}
+}
+
+func TestPolyMatrix_Mul(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		for trial := 0; trial < 8; trial++ {
+			r, k, c := 1+prng.Intn(4), 1+prng.Intn(4), 1+prng.Intn(4)
+			a := randomPolyMatrix(prng, field, r, k, 3)
+			b := randomPolyMatrix(prng, field, k, c, 3)
+			ab := a.Mul(b)
+			x := byte(prng.Intn(int(field.Size())))
+			if !ab.Evaluate(x).Equal(a.Evaluate(x).Mul(b.Evaluate(x))) {
+				t.Errorf("%v: (%v)(%v) at %d is not the product of the values", field, a, b, x)
+			}
+			if !ab.Transpose().Equal(b.Transpose().Mul(a.Transpose())) {
+				t.Errorf("%v: (AB)^T != B^T A^T for (%v), (%v)", field, a, b)
+			}
+			if !a.Mul(PolyIdentity(field, k)).Equal(a) || !a.Add(a).Equal(NewPolyMatrix(field, r, k)) {
+				t.Errorf("%v: identity or Add fails for (%v)", field, a)
+			}
+		}
+	}
+}
+
+func TestPolyMatrix_Determinant(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		for trial := 0; trial < 8; trial++ {
+			n := prng.Intn(5)
+			a := randomPolyMatrix(prng, field, n, n, 2)
+			det := a.Determinant()
+			for k := 0; k < 4; k++ {
+				x := byte(prng.Intn(int(field.Size())))
+				if det.Evaluate(x) != a.Evaluate(x).Determinant() {
+					t.Errorf("%v: det (%v) = (%v), but at %d the determinant is %d",
+						field, a, det, x, a.Evaluate(x).Determinant())
+				}
+			}
+			b := randomPolyMatrix(prng, field, n, n, 2)
+			if expected := det.Mul(b.Determinant()); !a.Mul(b).Determinant().Equal(expected) {
+				t.Errorf("%v: det(AB) != det(A)det(B) for (%v), (%v)", field, a, b)
+			}
+		}
+
+		// det(xI - C) is the characteristic polynomial.
+		c := randomMatrix(prng, field, 4, 4)
+		xI := PolyIdentity(field, 4)
+		for i := 0; i < 4; i++ {
+			xI.entries[i*4+i] = NewPolynomial(field, 0, 1)
+		}
+		if chi := xI.Add(c.PolyMatrix()).Determinant(); !chi.Equal(c.CharacteristicPolynomial()) {
+			t.Errorf("%v: det(xI - (%v)) = (%v), expected (%v)", field, c, chi, c.CharacteristicPolynomial())
+		}
+	}
+}
+
+func TestPolyMatrix_WeakPopov(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		for trial := 0; trial < 16; trial++ {
+			r, c := 1+prng.Intn(4), 1+prng.Intn(4)
+			m := randomPolyMatrix(prng, field, r, c, 1+prng.Intn(4))
+			if trial%4 == 0 && r > 1 {
+				// A dependent row.
+				for j := 0; j < c; j++ {
+					m.entries[(r-1)*c+j] = m.entries[j].Mul(NewPolynomial(field, 1, 1))
+				}
+			}
+			w, u := m.WeakPopov()
+			if !w.IsWeakPopov() {
+				t.Errorf("%v: (%v) is not in weak Popov form", field, w)
+			}
+			if !u.Mul(m).Equal(w) {
+				t.Errorf("%v: U*M != W for M=(%v), U=(%v), W=(%v)", field, m, u, w)
+			}
+			if det := u.Determinant(); det.IsZero() || det.Degree() != 0 {
+				t.Errorf("%v: (%v) is not unimodular", field, u)
+			}
+			if sumDegrees(w) > sumDegrees(m) {
+				t.Errorf("%v: row degrees grew from (%v) to (%v)", field, m, w)
+			}
+		}
+	}
+
+	// The rows (x**2, x) and (x, 1) are dependent: x times the second row
+	// cancels the first.
+	m := NewPolyMatrix(nil, 2, 2, NewPolynomial(nil, 0, 0, 1), NewPolynomial(nil, 0, 1),
+		NewPolynomial(nil, 0, 1), NewPolynomial(nil, 1))
+	w, _ := m.WeakPopov()
+	if positions := w.LeadingPositions(); positions[0] != -1 || positions[1] != 0 {
+		t.Errorf("expected leading positions [-1 0] for (%v), got %v", w, positions)
+	}
+}
+
+func TestPolyMatrix_panics(t *testing.T) {
+	type testrow struct {
+		fn       func()
+		expected error
+	}
+	a := NewPolyMatrix(nil, 2, 3)
+	for idx, row := range []testrow{
+		testrow{func() { NewPolyMatrix(nil, 1, 1, NewPolynomial(nil), NewPolynomial(nil)) }, ErrDimensionMismatch},
+		testrow{func() { NewPolyMatrix(nil, 1, 1, NewPolynomial(Poly210_g2)) }, ErrIncompatibleFields},
+		testrow{func() { a.Mul(a) }, ErrDimensionMismatch},
+		testrow{func() { a.Add(NewPolyMatrix(Poly210_g2, 2, 3)) }, ErrIncompatibleFields},
+		testrow{func() { a.Determinant() }, ErrDimensionMismatch},
+		testrow{func() { a.At(2, 0) }, ErrDimensionMismatch},
+	} {
+		if e := panicValue(row.fn); e != row.expected {
+			t.Errorf("[%d] expected panic(%v), got %v", idx, row.expected, e)
+		}
+	}
+}
+
+func randomPolyMatrix(prng *rand.Rand, field *GF, rows, cols, degree int) PolyMatrix {
+	m := NewPolyMatrix(field, rows, cols)
+	for i := range m.entries {
+		m.entries[i] = NewPolynomial(field, randomCoefficients(prng, int(field.Size()), prng.Intn(degree+1))...)
+	}
+	return m
+}
+
+func sumDegrees(m PolyMatrix) int {
+	sum := 0
+	for _, d := range m.RowDegrees() {
+		sum += d
+	}
+	return sum
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"math/big"
 	"strconv"
 )
 
@@ -19,11 +20,23 @@ type Polynomial struct {
 
 // NewPolynomial returns a new polynomial with the given coefficients.
 // Coefficients are in little-endian order; that is, the first coefficient is
-// the constant term, the second coefficient is the linear term, etc.
+// the constant term, the second coefficient is the linear term, etc.  The
+// coefficients are copied, so the caller may reuse the slice.
 func NewPolynomial(field *GF, coefficients ...byte) Polynomial {
 	if field == nil {
 		field = Default
 	}
+	coefficients = reduce(coefficients)
+	if coefficients != nil {
+		coefficients = append([]byte(nil), coefficients...)
+	}
+	return Polynomial{field, coefficients}
+}
+
+// wrapPolynomial returns a polynomial which takes ownership of the given
+// coefficients, without copying them.  The caller must not modify the slice
+// afterwards.
+func wrapPolynomial(field *GF, coefficients []byte) Polynomial {
 	return Polynomial{field, reduce(coefficients)}
 }
 
@@ -43,9 +56,13 @@ func (a Polynomial) Degree() uint {
 }
 
 // Coefficients returns the coefficients of the terms of this polynomial.  The
-// result is in little-endian order; see NewPolynomial for details.
+// result is in little-endian order; see NewPolynomial for details.  It is a
+// copy, which the caller may modify freely.
 func (a Polynomial) Coefficients() []byte {
-	return a.coefficients
+	if a.coefficients == nil {
+		return nil
+	}
+	return append([]byte(nil), a.coefficients...)
 }
 
 // Coefficient returns the coefficient of the i'th term.
@@ -68,7 +85,7 @@ func (a Polynomial) Scale(s byte) Polynomial {
 	for i, coeff_i := range a.coefficients {
 		coefficients[i] = a.field.Mul(coeff_i, s)
 	}
-	return NewPolynomial(a.field, coefficients...)
+	return wrapPolynomial(a.field, coefficients)
 }
 
 // Add returns the sum of one or more polynomials.
@@ -86,7 +103,7 @@ func (first Polynomial) Add(rest ...Polynomial) Polynomial {
 			sum[i] = first.field.Add(sum[i], ki)
 		}
 	}
-	return NewPolynomial(first.field, sum...)
+	return wrapPolynomial(first.field, sum)
 }
 
 // Mul returns the product of one or more polynomials.
@@ -96,18 +113,66 @@ func (first Polynomial) Mul(rest ...Polynomial) Polynomial {
 		if first.field != next.field {
 			panic(ErrIncompatibleFields)
 		}
-		a, b := prod, next.coefficients
-		newprod := make([]byte, len(a)+len(b))
-		for bi := 0; bi < len(b); bi++ {
-			for ai := 0; ai < len(a); ai++ {
-				newprod[ai+bi] = first.field.Add(
-					newprod[ai+bi],
-					first.field.Mul(a[ai], b[bi]))
-			}
-		}
-		prod = reduce(newprod)
+		prod = polyMul(first.field, prod, next.coefficients)
+	}
+	return wrapPolynomial(first.field, prod)
+}
+
+// LeadingCoefficient returns the coefficient of the highest-degree term, or 0
+// for the zero polynomial.
+func (a Polynomial) LeadingCoefficient() byte {
+	if a.IsZero() {
+		return 0
 	}
-	return NewPolynomial(first.field, prod...)
+	return a.coefficients[len(a.coefficients)-1]
+}
+
+// Monic returns this polynomial scaled so that its leading coefficient is 1.
+// The zero polynomial is returned unchanged.
+func (a Polynomial) Monic() Polynomial {
+	if a.IsZero() {
+		return a
+	}
+	return a.Scale(a.field.Inv(a.LeadingCoefficient()))
+}
+
+// DivMod returns the quotient and remainder of a divided by b, such that
+// a = q*b + r and deg(r) < deg(b).  It panics with ErrDivByZero if b is the
+// zero polynomial.
+func (a Polynomial) DivMod(b Polynomial) (q, r Polynomial) {
+	if a.field != b.field {
+		panic(ErrIncompatibleFields)
+	}
+	if b.IsZero() {
+		panic(ErrDivByZero)
+	}
+	if len(a.coefficients) < len(b.coefficients) {
+		return Polynomial{a.field, nil}, a
+	}
+	rem := expand(len(a.coefficients), a.coefficients)
+	quo := make([]byte, len(a.coefficients)-len(b.coefficients)+1)
+	polyDivMod(a.field, quo, rem, b.coefficients)
+	return wrapPolynomial(a.field, quo),
+		wrapPolynomial(a.field, rem[:len(b.coefficients)-1])
+}
+
+// Mod returns the remainder of a divided by b.  It panics with ErrDivByZero
+// if b is the zero polynomial.
+func (a Polynomial) Mod(b Polynomial) Polynomial {
+	_, r := a.DivMod(b)
+	return r
+}
+
+// GCD returns the monic greatest common divisor of a and b.  The GCD of two
+// zero polynomials is the zero polynomial.
+func (a Polynomial) GCD(b Polynomial) Polynomial {
+	if a.field != b.field {
+		panic(ErrIncompatibleFields)
+	}
+	for !b.IsZero() {
+		a, b = b, a.Mod(b)
+	}
+	return a.Monic()
 }
 
 // GoString returns a Go-syntax representation of this polynomial.
@@ -184,15 +249,156 @@ func (a Polynomial) Less(b Polynomial) bool {
 	return a.Compare(b) < 0
 }
 
-// Evaluate substitutes for x and returns the resulting value.
+// Evaluate substitutes for x and returns the resulting value.  Use
+// EvaluateMany to evaluate at many points at once.
 func (a Polynomial) Evaluate(x byte) byte {
-	var sum byte = 0
-	var pow byte = 1
-	for _, k := range a.coefficients {
-		sum = a.field.Add(sum, a.field.Mul(k, pow))
-		pow = a.field.Mul(pow, x)
+	return horner(a.field, a.coefficients, x)
+}
+
+// Derivative returns the formal derivative of this polynomial.
+//
+// Because the coefficient field has characteristic 2, the derivative of x^i
+// is i*x^(i-1) where i is taken mod 2: every even-degree term vanishes, and
+// the derivative of any perfect square is zero.  Use HasseDerivative when the
+// higher-order derivatives are needed, since repeating Derivative always
+// gives zero after the second step.
+func (a Polynomial) Derivative() Polynomial {
+	return a.HasseDerivative(1)
+}
+
+// HasseDerivative returns the k'th Hasse derivative of this polynomial:
+//
+//	D^(k) ∑_i a_i*x^i = ∑_i C(i,k)*a_i*x^(i-k)
+//
+// where the binomial coefficient C(i,k) is reduced mod 2.  Unlike repeated
+// formal derivatives, Hasse derivatives detect roots of multiplicity > k in
+// characteristic 2: r is a root of multiplicity at least m iff D^(k)(r) == 0
+// for all k < m.
+func (a Polynomial) HasseDerivative(k uint) Polynomial {
+	if k == 0 {
+		return a
+	}
+	n := uint(len(a.coefficients))
+	if k >= n {
+		return Polynomial{a.field, nil}
+	}
+	coefficients := make([]byte, n-k)
+	for i := k; i < n; i++ {
+		// By Lucas's theorem, C(i,k) is odd iff every bit set in k
+		// is also set in i.
+		if i&k == k {
+			coefficients[i-k] = a.coefficients[i]
+		}
+	}
+	return wrapPolynomial(a.field, coefficients)
+}
+
+// polyDivMod divides rem by the (non-zero, reduced) divisor in place.  On
+// return, the low len(divisor)-1 entries of rem hold the remainder and the
+// higher entries are zero.  If quo is non-nil, it receives the quotient and
+// must have room for len(rem)-len(divisor)+1 coefficients.
+func polyDivMod(field *GF, quo, rem, divisor []byte) {
+	var buf [32]uint
+	polyDivModLogs(field, quo, rem, divisor, logSlice(field, buf[:0], divisor))
+}
+
+// polyDivModLogs is polyDivMod, given the logarithms of the divisor's
+// coefficients as computed by logSlice.
+func polyDivModLogs(field *GF, quo, rem, divisor []byte, logs []uint) {
+	n := len(divisor) - 1
+	lead := field.Inv(divisor[n])
+	for i := len(rem) - 1; i >= n; i-- {
+		c := rem[i]
+		if c == 0 {
+			continue
+		}
+		c = field.Mul(c, lead)
+		if quo != nil {
+			quo[i-n] = c
+		}
+		logc := uint(field.log[c])
+		for j, dj := range divisor {
+			if dj != 0 {
+				rem[i-n+j] ^= field.exp[logc+logs[j]]
+			}
+		}
+	}
+}
+
+// polySqrMod returns a**2 mod m.  In characteristic 2 squaring is the
+// Frobenius map, (∑ a_i*x^i)**2 = ∑ a_i**2 * x^(2i), so no cross terms need to
+// be computed before the reduction.
+func polySqrMod(field *GF, a, m []byte) []byte {
+	if len(a) == 0 {
+		return nil
+	}
+	sq := make([]byte, 2*len(a)-1)
+	for i, ai := range a {
+		sq[2*i] = field.Mul(ai, ai)
+	}
+	if len(sq) < len(m) {
+		return reduce(sq)
 	}
-	return sum
+	polyDivMod(field, nil, sq, m)
+	return reduce(sq[:len(m)-1])
+}
+
+// polyMulMod returns a*b mod m.
+func polyMulMod(field *GF, a, b, m []byte) []byte {
+	prod := polyMul(field, a, b)
+	if len(prod) < len(m) {
+		return prod
+	}
+	polyDivMod(field, nil, prod, m)
+	return reduce(prod[:len(m)-1])
+}
+
+// polyPowMod returns a**e mod m by left-to-right square-and-multiply.
+func polyPowMod(field *GF, a []byte, e *big.Int, m []byte) []byte {
+	result := []byte{1}
+	if len(m) == 1 {
+		return nil
+	}
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		result = polySqrMod(field, result, m)
+		if e.Bit(i) != 0 {
+			result = polyMulMod(field, result, a, m)
+		}
+	}
+	return result
+}
+
+// polyXPow2Mod returns x**(2**i) mod m, computed by i Frobenius squarings.
+func polyXPow2Mod(field *GF, i uint, m []byte) []byte {
+	x := []byte{0, 1}
+	if len(m) <= len(x) {
+		x = append([]byte(nil), x...)
+		polyDivMod(field, nil, x, m)
+		x = reduce(x[:len(m)-1])
+	}
+	for ; i > 0; i-- {
+		x = polySqrMod(field, x, m)
+	}
+	return x
+}
+
+// logSlice returns the logarithms of the given coefficients, for use when
+// multiplying them all by the same value, as polyDivModLogs does.  The result
+// is written to the start of buf's backing array if its capacity suffices,
+// whatever buf's length, so that callers may pass buf[:0] of a fixed-size
+// array to keep short slices on the stack, or keep the result and pass it
+// back in to reuse it.  Zero coefficients have no logarithm, and their
+// entries are 0, which is also the logarithm of 1, so callers must test the
+// coefficients themselves for zero, not the logarithms.
+func logSlice(field *GF, buf []uint, coefficients []byte) []uint {
+	if cap(buf) < len(coefficients) {
+		buf = make([]uint, len(coefficients))
+	}
+	logs := buf[:len(coefficients)]
+	for i, c := range coefficients {
+		logs[i] = uint(field.log[c])
+	}
+	return logs
 }
 
 func reduce(coefficients []byte) []byte {
@@ -202,6 +408,9 @@ func reduce(coefficients []byte) []byte {
 		}
 		coefficients = coefficients[:i]
 	}
+	if len(coefficients) == 0 {
+		return nil
+	}
 	return coefficients
 }
 
@@ -19,6 +19,10 @@ func TestNewPolynomial(t *testing.T) {
 			"0",
 			"NewPolynomial(Poly84320_g2)",
 			Poly84320_g2, 0, nil},
+		testrow{NewPolynomial(nil, 0, 0),
+			"0",
+			"NewPolynomial(Poly84320_g2)",
+			Poly84320_g2, 0, nil},
 		testrow{NewPolynomial(nil, 1),
 			"1",
 			"NewPolynomial(Poly84320_g2, 1)",
@@ -212,6 +216,114 @@ func TestPolynomial_axioms(t *testing.T) {
 	}
 }
 
+func TestPolynomial_Derivative(t *testing.T) {
+	type testrow struct {
+		input    Polynomial
+		k        uint
+		expected Polynomial
+	}
+	for _, row := range []testrow{
+		testrow{NewPolynomial(nil), 1,
+			NewPolynomial(nil)},
+		testrow{NewPolynomial(nil, 7), 1,
+			NewPolynomial(nil)},
+		testrow{NewPolynomial(nil, 3, 5), 1,
+			NewPolynomial(nil, 5)},
+		testrow{NewPolynomial(nil, 1, 2, 3, 4, 5), 1,
+			NewPolynomial(nil, 2, 0, 4)},
+		testrow{NewPolynomial(nil, 1, 0, 1), 1,
+			NewPolynomial(nil)},
+		testrow{NewPolynomial(nil, 1, 2, 3, 4, 5), 0,
+			NewPolynomial(nil, 1, 2, 3, 4, 5)},
+		testrow{NewPolynomial(nil, 1, 2, 3, 4, 5), 2,
+			NewPolynomial(nil, 3, 4)},
+		testrow{NewPolynomial(nil, 1, 2, 3, 4, 5, 6, 7), 2,
+			NewPolynomial(nil, 3, 4, 0, 0, 7)},
+		testrow{NewPolynomial(nil, 1, 2, 3, 4, 5, 6, 7), 4,
+			NewPolynomial(nil, 5, 6, 7)},
+		testrow{NewPolynomial(nil, 1, 2, 3, 4, 5), 5,
+			NewPolynomial(nil)},
+	} {
+		actual := row.input.HasseDerivative(row.k)
+		if !actual.Equal(row.expected) {
+			t.Errorf("expected D^(%d)(%v)=(%v), got (%v)",
+				row.k, row.input, row.expected, actual)
+		}
+		if row.k == 1 {
+			actual = row.input.Derivative()
+			if !actual.Equal(row.expected) {
+				t.Errorf("expected (%v)'=(%v), got (%v)",
+					row.input, row.expected, actual)
+			}
+		}
+	}
+}
+
+func TestPolynomial_Derivative_axioms(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		for deg := 0; deg <= 12; deg++ {
+			for trial := 0; trial < 16; trial++ {
+				var a, b []byte
+				bdeg := prng.Intn(deg + 1)
+				for j := 0; j <= deg; j++ {
+					a = append(a, byte(prng.Intn(int(field.Size()))))
+				}
+				for j := 0; j <= bdeg; j++ {
+					b = append(b, byte(prng.Intn(int(field.Size()))))
+				}
+				checkDerivativeAxioms(
+					t,
+					NewPolynomial(field, a...),
+					NewPolynomial(field, b...),
+					NewPolynomial(field))
+			}
+		}
+	}
+}
+
+func TestPolynomial_DivMod(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		n := int(field.Size())
+		for trial := 0; trial < 64; trial++ {
+			var a, b []byte
+			for i := prng.Intn(16); i >= 0; i-- {
+				a = append(a, byte(prng.Intn(n)))
+			}
+			for i := prng.Intn(8); i >= 0; i-- {
+				b = append(b, byte(prng.Intn(n)))
+			}
+			b = append(b, byte(1+prng.Intn(n-1)))
+			pa := NewPolynomial(field, a...)
+			pb := NewPolynomial(field, b...)
+			q, r := pa.DivMod(pb)
+			if !r.IsZero() && r.Degree() >= pb.Degree() {
+				t.Errorf("expected deg(r) < deg(b) for (%v)/(%v), got r=(%v)",
+					pa, pb, r)
+			}
+			if qbr := q.Mul(pb).Add(r); !qbr.Equal(pa) {
+				t.Errorf("expected q*b+r=a for a=(%v) b=(%v), got q=(%v) r=(%v)",
+					pa, pb, q, r)
+			}
+			g := pa.GCD(pb)
+			if !pa.Mod(g).IsZero() || !pb.Mod(g).IsZero() {
+				t.Errorf("expected gcd(%v, %v)=(%v) to divide both",
+					pa, pb, g)
+			}
+		}
+	}
+}
+
+func TestPolynomial_DivMod_zero(t *testing.T) {
+	e := panicValue(func() {
+		NewPolynomial(nil, 1, 2).DivMod(NewPolynomial(nil))
+	})
+	if e != ErrDivByZero {
+		t.Errorf("expected panic(ErrDivByZero), got %v", e)
+	}
+}
+
 func TestPolynomial_Add_incompatible(t *testing.T) {
 	e := panicValue(func() {
 		_ = NewPolynomial(Poly210_g2).
@@ -512,6 +624,54 @@ func checkMulAxioms(t *testing.T, a, b, c, zero, one Polynomial) {
 	}
 }
 
+func checkDerivativeAxioms(t *testing.T, a, b, zero Polynomial) {
+	// (a+b)' = a' + b'
+	lhs := a.Add(b).Derivative()
+	rhs := a.Derivative().Add(b.Derivative())
+	if !lhs.Equal(rhs) {
+		t.Errorf(
+			"derivative not additive for a=%#v b=%#v: "+
+				"got (a+b)'=%#v vs a'+b'=%#v", a, b, lhs, rhs)
+	}
+
+	// (a*b)' = a'*b + a*b'
+	lhs = a.Mul(b).Derivative()
+	rhs = a.Derivative().Mul(b).Add(a.Mul(b.Derivative()))
+	if !lhs.Equal(rhs) {
+		t.Errorf(
+			"product rule fails for a=%#v b=%#v: "+
+				"got (a*b)'=%#v vs a'*b+a*b'=%#v", a, b, lhs, rhs)
+	}
+
+	// a'' = 0 in characteristic 2
+	if dd := a.Derivative().Derivative(); !dd.Equal(zero) {
+		t.Errorf(
+			"second derivative not zero for a=%#v: "+
+				"got a''=%#v", a, dd)
+	}
+
+	// D^(k)(a*b) = ∑_{i+j=k} D^(i)(a)*D^(j)(b)
+	for k := uint(0); k <= a.Degree()+b.Degree()+1; k++ {
+		lhs = a.Mul(b).HasseDerivative(k)
+		rhs = zero
+		for i := uint(0); i <= k; i++ {
+			rhs = rhs.Add(a.HasseDerivative(i).Mul(b.HasseDerivative(k - i)))
+		}
+		if !lhs.Equal(rhs) {
+			t.Errorf(
+				"Leibniz rule fails for a=%#v b=%#v k=%d: "+
+					"got D(a*b)=%#v vs ∑D(a)*D(b)=%#v", a, b, k, lhs, rhs)
+		}
+	}
+
+	// D^(1) = '
+	if h, d := a.HasseDerivative(1), a.Derivative(); !h.Equal(d) {
+		t.Errorf(
+			"first Hasse derivative differs from derivative for a=%#v: "+
+				"got D^(1)(a)=%#v vs a'=%#v", a, h, d)
+	}
+}
+
 func equalBytes(a, b []byte) bool {
 	if len(a) != len(b) {
 		return false
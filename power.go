@@ -0,0 +1,130 @@
+package galoisfield
+
+import (
+	"errors"
+	"math/big"
+)
+
+var (
+	ErrNegativeExponent = errors.New("exponent is negative")
+)
+
+// Compose returns the composition a(g(x)), i.e. the result of substituting
+// the polynomial g for x in a.  It panics with ErrIncompatibleFields if a and
+// g are drawn from different fields.
+func (a Polynomial) Compose(g Polynomial) Polynomial {
+	if a.field != g.field {
+		panic(ErrIncompatibleFields)
+	}
+	field := a.field
+	// Horner's rule, with polynomial multiplication.
+	var result []byte
+	for i := len(a.coefficients) - 1; i >= 0; i-- {
+		result = polyMul(field, result, g.coefficients)
+		if len(result) == 0 {
+			result = []byte{0}
+		}
+		result[0] ^= a.coefficients[i]
+		result = reduce(result)
+	}
+	return wrapPolynomial(field, result)
+}
+
+// Pow returns a**e.  By convention, a**0 = 1 even if a is zero.
+//
+// Squaring is the Frobenius map in characteristic 2, so it costs one field
+// multiplication per coefficient; only the set bits of e need a full
+// multiplication.
+func (a Polynomial) Pow(e uint) Polynomial {
+	field := a.field
+	result := []byte{1}
+	for bit := highBit(e); bit > 0; bit >>= 1 {
+		result = polySqr(field, result)
+		if e&bit != 0 {
+			result = polyMul(field, result, a.coefficients)
+		}
+	}
+	return wrapPolynomial(field, result)
+}
+
+// PowMod returns a**e mod m, by left-to-right square-and-multiply with a
+// reduction after every step.  It panics with ErrDivByZero if m is zero,
+// with ErrNegativeExponent if e is negative, or with ErrIncompatibleFields if
+// a and m are drawn from different fields.
+func (a Polynomial) PowMod(e *big.Int, m Polynomial) Polynomial {
+	if a.field != m.field {
+		panic(ErrIncompatibleFields)
+	}
+	if m.IsZero() {
+		panic(ErrDivByZero)
+	}
+	if e.Sign() < 0 {
+		panic(ErrNegativeExponent)
+	}
+	field := a.field
+	base := polyRem(field, a.coefficients, m.coefficients)
+	return wrapPolynomial(field, polyPowMod(field, base, e, m.coefficients))
+}
+
+// XPowMod returns x**e mod m.  It panics with ErrDivByZero if m is zero, or
+// with ErrNegativeExponent if e is negative.
+//
+// This is the special case of PowMod at the heart of irreducibility testing,
+// root finding and LFSR jump-ahead.  Each step is a Frobenius squaring,
+// followed for set bits of e by a multiplication by x, which is just a shift
+// and a single reduction step.  In particular, x**(2**i) mod m takes i
+// squarings and nothing else.
+func XPowMod(e *big.Int, m Polynomial) Polynomial {
+	if m.IsZero() {
+		panic(ErrDivByZero)
+	}
+	if e.Sign() < 0 {
+		panic(ErrNegativeExponent)
+	}
+	field := m.field
+	mod := m.coefficients
+	if len(mod) == 1 {
+		return Polynomial{field, nil}
+	}
+	result := []byte{1}
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		result = polySqrMod(field, result, mod)
+		if e.Bit(i) != 0 {
+			result = polyMulXMod(field, result, mod)
+		}
+	}
+	return wrapPolynomial(field, result)
+}
+
+// polySqr returns a**2, using the Frobenius map.
+func polySqr(field *GF, a []byte) []byte {
+	if len(a) == 0 {
+		return nil
+	}
+	sq := make([]byte, 2*len(a)-1)
+	for i, ai := range a {
+		sq[2*i] = field.Mul(ai, ai)
+	}
+	return sq
+}
+
+// polyMulXMod returns x*a mod m, where a is already reduced modulo m.
+func polyMulXMod(field *GF, a, m []byte) []byte {
+	shifted := make([]byte, len(a)+1)
+	copy(shifted[1:], a)
+	if len(shifted) < len(m) {
+		return reduce(shifted)
+	}
+	polyDivMod(field, nil, shifted, m)
+	return reduce(shifted[:len(m)-1])
+}
+
+// highBit returns the highest set bit of e, or 0 if e is 0.
+func highBit(e uint) uint {
+	var bit uint
+	for e != 0 {
+		bit = e &^ (e - 1)
+		e &= e - 1
+	}
+	return bit
+}
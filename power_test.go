@@ -0,0 +1,142 @@
+package galoisfield
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func TestPolynomial_Compose(t *testing.T) {
+	type testrow struct {
+		a, g     Polynomial
+		expected Polynomial
+	}
+	for idx, row := range []testrow{
+		testrow{NewPolynomial(nil), NewPolynomial(nil, 1, 2),
+			NewPolynomial(nil)},
+		testrow{NewPolynomial(nil, 5), NewPolynomial(nil, 1, 2),
+			NewPolynomial(nil, 5)},
+		testrow{NewPolynomial(nil, 0, 1), NewPolynomial(nil, 1, 2, 3),
+			NewPolynomial(nil, 1, 2, 3)},
+		testrow{NewPolynomial(nil, 1, 2, 3), NewPolynomial(nil, 0, 1),
+			NewPolynomial(nil, 1, 2, 3)},
+		testrow{NewPolynomial(nil, 1, 2, 3), NewPolynomial(nil),
+			NewPolynomial(nil, 1)},
+		testrow{NewPolynomial(nil, 0, 0, 1), NewPolynomial(nil, 1, 1),
+			NewPolynomial(nil, 1, 0, 1)},
+		testrow{NewPolynomial(nil, 1, 1, 1), NewPolynomial(nil, 0, 0, 1),
+			NewPolynomial(nil, 1, 0, 1, 0, 1)},
+	} {
+		actual := row.a.Compose(row.g)
+		if !actual.Equal(row.expected) {
+			t.Errorf("[%2d] expected (%v)∘(%v) = (%v), got (%v)",
+				idx, row.a, row.g, row.expected, actual)
+		}
+	}
+}
+
+func TestPolynomial_Compose_random(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		q := int(field.Size())
+		for trial := 0; trial < 16; trial++ {
+			a := NewPolynomial(field, randomCoefficients(prng, q, prng.Intn(6))...)
+			g := NewPolynomial(field, randomCoefficients(prng, q, prng.Intn(6))...)
+			composed := a.Compose(g)
+			for x := 0; x < q; x++ {
+				expected := a.Evaluate(g.Evaluate(byte(x)))
+				if actual := composed.Evaluate(byte(x)); actual != expected {
+					t.Errorf("%v: (%v)∘(%v) at %d: expected %d, got %d",
+						field, a, g, x, expected, actual)
+				}
+			}
+		}
+	}
+}
+
+func TestPolynomial_Pow(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		q := int(field.Size())
+		for trial := 0; trial < 8; trial++ {
+			a := NewPolynomial(field, randomCoefficients(prng, q, prng.Intn(5))...)
+			expected := NewPolynomial(field, 1)
+			for e := uint(0); e < 20; e++ {
+				if actual := a.Pow(e); !actual.Equal(expected) {
+					t.Errorf("%v: expected (%v)**%d = (%v), got (%v)",
+						field, a, e, expected, actual)
+				}
+				expected = expected.Mul(a)
+			}
+		}
+	}
+}
+
+func TestPolynomial_PowMod(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		q := int(field.Size())
+		for trial := 0; trial < 8; trial++ {
+			a := NewPolynomial(field, randomCoefficients(prng, q, prng.Intn(8))...)
+			m := NewPolynomial(field, randomCoefficients(prng, q, 1+prng.Intn(6))...)
+			x := NewPolynomial(field, 0, 1)
+			for e := int64(0); e < 40; e++ {
+				be := big.NewInt(e)
+				expected := a.Pow(uint(e)).Mod(m)
+				if actual := a.PowMod(be, m); !actual.Equal(expected) {
+					t.Errorf("%v: expected (%v)**%d mod (%v) = (%v), got (%v)",
+						field, a, e, m, expected, actual)
+				}
+				expected = x.Pow(uint(e)).Mod(m)
+				if actual := XPowMod(be, m); !actual.Equal(expected) {
+					t.Errorf("%v: expected x**%d mod (%v) = (%v), got (%v)",
+						field, e, m, expected, actual)
+				}
+			}
+		}
+	}
+}
+
+func TestXPowMod_frobenius(t *testing.T) {
+	// x**(q**n) = x mod any irreducible polynomial of degree n.
+	for _, field := range fields {
+		for _, n := range []uint{1, 2, 5, 16} {
+			m := LowWeightIrreducible(field, n)
+			e := new(big.Int).Lsh(big.NewInt(1), uint(field.k)*n)
+			if actual := XPowMod(e, m); !actual.Equal(NewPolynomial(field, 0, 1).Mod(m)) {
+				t.Errorf("%v: expected x**(q**%d) = x mod (%v), got (%v)",
+					field, n, m, actual)
+			}
+		}
+	}
+}
+
+func TestPolynomial_PowMod_zero(t *testing.T) {
+	e := panicValue(func() {
+		NewPolynomial(nil, 1, 2).PowMod(big.NewInt(3), NewPolynomial(nil))
+	})
+	if e != ErrDivByZero {
+		t.Errorf("expected panic(ErrDivByZero), got %v", e)
+	}
+	e = panicValue(func() {
+		XPowMod(big.NewInt(3), NewPolynomial(nil))
+	})
+	if e != ErrDivByZero {
+		t.Errorf("expected panic(ErrDivByZero), got %v", e)
+	}
+}
+
+func TestPolynomial_PowMod_negative(t *testing.T) {
+	e := panicValue(func() {
+		NewPolynomial(nil, 1, 2).PowMod(big.NewInt(-3), NewPolynomial(nil, 1, 0, 1))
+	})
+	if e != ErrNegativeExponent {
+		t.Errorf("expected panic(ErrNegativeExponent), got %v", e)
+	}
+	e = panicValue(func() {
+		XPowMod(big.NewInt(-3), NewPolynomial(nil, 1, 0, 1))
+	})
+	if e != ErrNegativeExponent {
+		t.Errorf("expected panic(ErrNegativeExponent), got %v", e)
+	}
+}
@@ -0,0 +1,193 @@
+package galoisfield
+
+import (
+	"errors"
+)
+
+var (
+	ErrPole              = errors.New("rational function has a pole at this point")
+	ErrNoPadeApproximant = errors.New("no Padé approximant of the given degrees exists")
+)
+
+// RationalFunction implements ratios p/q of polynomials with coefficients
+// drawn from a Galois field.  It is always kept in lowest terms, with a
+// monic denominator, so equal rational functions have equal representations.
+//
+// Like Polynomial, RationalFunction is an immutable value type.
+type RationalFunction struct {
+	num, den Polynomial
+}
+
+// NewRationalFunction returns the rational function num/den in lowest terms.
+// It panics with ErrDivByZero if den is zero, or with ErrIncompatibleFields
+// if num and den are drawn from different fields.
+func NewRationalFunction(num, den Polynomial) RationalFunction {
+	if num.field != den.field {
+		panic(ErrIncompatibleFields)
+	}
+	if den.IsZero() {
+		panic(ErrDivByZero)
+	}
+	if num.IsZero() {
+		return RationalFunction{num, NewPolynomial(num.field, 1)}
+	}
+	if g := num.GCD(den); g.Degree() > 0 {
+		num, _ = num.DivMod(g)
+		den, _ = den.DivMod(g)
+	}
+	s := num.field.Inv(den.LeadingCoefficient())
+	return RationalFunction{num.Scale(s), den.Scale(s)}
+}
+
+// Field returns the Galois field from which this rational function's
+// coefficients are drawn.
+func (r RationalFunction) Field() *GF { return r.num.field }
+
+// Numerator returns the numerator of this rational function in lowest terms.
+func (r RationalFunction) Numerator() Polynomial { return r.num }
+
+// Denominator returns the monic denominator of this rational function in
+// lowest terms.
+func (r RationalFunction) Denominator() Polynomial { return r.den }
+
+// IsZero returns true iff this rational function is zero.
+func (r RationalFunction) IsZero() bool { return r.num.IsZero() }
+
+// Add returns the sum of one or more rational functions.
+func (first RationalFunction) Add(rest ...RationalFunction) RationalFunction {
+	sum := first
+	for _, next := range rest {
+		sum = NewRationalFunction(
+			sum.num.Mul(next.den).Add(next.num.Mul(sum.den)),
+			sum.den.Mul(next.den))
+	}
+	return sum
+}
+
+// Mul returns the product of one or more rational functions.
+func (first RationalFunction) Mul(rest ...RationalFunction) RationalFunction {
+	prod := first
+	for _, next := range rest {
+		prod = NewRationalFunction(prod.num.Mul(next.num), prod.den.Mul(next.den))
+	}
+	return prod
+}
+
+// Div returns r/s.  It panics with ErrDivByZero if s is zero.
+func (r RationalFunction) Div(s RationalFunction) RationalFunction {
+	return r.Mul(s.Inv())
+}
+
+// Inv returns the reciprocal of r.  It panics with ErrDivByZero if r is
+// zero.
+func (r RationalFunction) Inv() RationalFunction {
+	return NewRationalFunction(r.den, r.num)
+}
+
+// Evaluate substitutes for x and returns the resulting value.  It returns
+// ErrPole if the denominator vanishes at x; since r is in lowest terms, this
+// happens exactly at the poles of r.
+func (r RationalFunction) Evaluate(x byte) (byte, error) {
+	d := r.den.Evaluate(x)
+	if d == 0 {
+		return 0, ErrPole
+	}
+	return r.Field().Div(r.num.Evaluate(x), d), nil
+}
+
+// Series returns the first n coefficients of the power series expansion of r
+// about x = 0, as a polynomial of degree less than n.  It panics with ErrPole
+// if r has a pole at 0.
+func (r RationalFunction) Series(n uint) Polynomial {
+	field := r.Field()
+	q := r.den.coefficients
+	if q[0] == 0 {
+		panic(ErrPole)
+	}
+	inv := field.Inv(q[0])
+	series := make([]byte, n)
+	for k := range series {
+		c := r.num.Coefficient(uint(k))
+		for j := 1; j < len(q) && j <= k; j++ {
+			c ^= field.Mul(q[j], series[k-j])
+		}
+		series[k] = field.Mul(c, inv)
+	}
+	return wrapPolynomial(field, reduce(series))
+}
+
+// Pade returns the Padé approximant p/q of the power series s, such that
+// deg p <= m, deg q <= n, q(0) != 0, and q*s = p mod x**(m+n+1).  Only the
+// first m+n+1 coefficients of s are used.  It returns ErrNoPadeApproximant
+// if no such p/q exists.
+//
+// The approximant is found by the extended Euclidean algorithm on x**(m+n+1)
+// and s, stopped at the first remainder of degree at most m.  This is the
+// same computation as solving the key equation of Reed-Solomon decoding,
+// where s is the syndrome polynomial, q the error locator and p the error
+// evaluator.
+func Pade(s Polynomial, m, n uint) (RationalFunction, error) {
+	field := s.field
+	N := m + n + 1
+	modulus := make([]byte, N+1)
+	modulus[N] = 1
+	r0, r1 := wrapPolynomial(field, modulus), s
+	if s.Degree() >= N {
+		r1 = wrapPolynomial(field, reduce(append([]byte(nil), s.coefficients[:N]...)))
+	}
+	t0, t1 := Polynomial{field, nil}, NewPolynomial(field, 1)
+	for !r1.IsZero() && r1.Degree() > m {
+		quo, rem := r0.DivMod(r1)
+		r0, r1 = r1, rem
+		t0, t1 = t1, t0.Add(quo.Mul(t1))
+	}
+	// Every solution of the congruence is a multiple of (r1, t1), so an
+	// approximant exists iff r1/t1 in lowest terms has q(0) != 0 and still
+	// satisfies the congruence.
+	approx := NewRationalFunction(r1, t1)
+	if approx.den.Coefficient(0) == 0 {
+		return RationalFunction{}, ErrNoPadeApproximant
+	}
+	residual := approx.den.Mul(s).Add(approx.num).coefficients
+	if uint(len(residual)) > N {
+		residual = residual[:N]
+	}
+	if len(reduce(residual)) != 0 {
+		return RationalFunction{}, ErrNoPadeApproximant
+	}
+	return approx, nil
+}
+
+// GoString returns a Go-syntax representation of this rational function.
+func (r RationalFunction) GoString() string {
+	return "NewRationalFunction(" + r.num.GoString() + ", " + r.den.GoString() + ")"
+}
+
+// String returns a human-readable algebraic representation of this rational
+// function, such as "(x + 1)/(x^2 + 3)", or just the numerator if the
+// denominator is 1.
+func (r RationalFunction) String() string {
+	if r.den.Degree() == 0 {
+		return r.num.String()
+	}
+	return "(" + r.num.String() + ")/(" + r.den.String() + ")"
+}
+
+// Compare defines a total order for rational functions: -1 if a < b, 0 if
+// a == b, +1 if a > b.  They are ordered by numerator, then by denominator.
+func (a RationalFunction) Compare(b RationalFunction) int {
+	if cmp := a.num.Compare(b.num); cmp != 0 {
+		return cmp
+	}
+	return a.den.Compare(b.den)
+}
+
+// Equal returns true iff a == b.
+func (a RationalFunction) Equal(b RationalFunction) bool {
+	return a.Compare(b) == 0
+}
+
+// Less returns true iff a < b.
+func (a RationalFunction) Less(b RationalFunction) bool {
+	return a.Compare(b) < 0
+}
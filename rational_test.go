@@ -0,0 +1,138 @@
+package galoisfield
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestNewRationalFunction(t *testing.T) {
+	type testrow struct {
+		num, den Polynomial
+		expected string
+	}
+	for idx, row := range []testrow{
+		testrow{NewPolynomial(nil), NewPolynomial(nil, 5, 7), "0"},
+		testrow{NewPolynomial(nil, 1, 0, 1), NewPolynomial(nil, 1, 1), "x + 1"},
+		testrow{NewPolynomial(nil, 2), NewPolynomial(nil, 2, 0, 2), "(1)/(x^2 + 1)"},
+		testrow{NewPolynomial(nil, 0, 1), NewPolynomial(nil, 3), "244x"},
+		testrow{NewPolynomial(nil, 0, 1, 1), NewPolynomial(nil, 0, 0, 1, 1), "(1)/(x)"},
+	} {
+		r := NewRationalFunction(row.num, row.den)
+		if actual := r.String(); actual != row.expected {
+			t.Errorf("[%d] expected (%v)/(%v) = %q, got %q", idx, row.num, row.den, row.expected, actual)
+		}
+		if r.Denominator().LeadingCoefficient() != 1 {
+			t.Errorf("[%d] expected a monic denominator, got (%v)", idx, r.Denominator())
+		}
+	}
+}
+
+func TestRationalFunction_arithmetic(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		q := int(field.Size())
+		for trial := 0; trial < 16; trial++ {
+			a := randomRationalFunction(prng, field, 5, 4)
+			b := randomRationalFunction(prng, field, 5, 4)
+			sum, prod := a.Add(b), a.Mul(b)
+			var quo RationalFunction
+			if !b.IsZero() {
+				quo = a.Div(b)
+			}
+			for x := 0; x < q; x++ {
+				va, ea := a.Evaluate(byte(x))
+				vb, eb := b.Evaluate(byte(x))
+				if ea != nil || eb != nil {
+					continue
+				}
+				if v, err := sum.Evaluate(byte(x)); err != nil || v != va^vb {
+					t.Errorf("%v: (%v) + (%v) at %d: expected %d, got %d, %v", field, a, b, x, va^vb, v, err)
+				}
+				if v, err := prod.Evaluate(byte(x)); err != nil || v != field.Mul(va, vb) {
+					t.Errorf("%v: (%v) * (%v) at %d: expected %d, got %d, %v",
+						field, a, b, x, field.Mul(va, vb), v, err)
+				}
+				if vb != 0 && !b.IsZero() {
+					if v, err := quo.Evaluate(byte(x)); err != nil || v != field.Div(va, vb) {
+						t.Errorf("%v: (%v) / (%v) at %d: expected %d, got %d, %v",
+							field, a, b, x, field.Div(va, vb), v, err)
+					}
+				}
+			}
+			if !a.Add(b).Add(b).Equal(a) || !a.Mul(b).Equal(b.Mul(a)) {
+				t.Errorf("%v: axioms fail for (%v), (%v)", field, a, b)
+			}
+		}
+	}
+}
+
+func TestRationalFunction_Evaluate_pole(t *testing.T) {
+	r := NewRationalFunction(NewPolynomial(nil, 1, 1, 1), NewPolynomial(nil, 1, 1))
+	if _, err := r.Evaluate(1); err != ErrPole {
+		t.Errorf("expected ErrPole at 1 for (%v), got %v", r, err)
+	}
+	if v, err := r.Evaluate(0); err != nil || v != 1 {
+		t.Errorf("expected (%v)(0) = 1, got %d, %v", r, v, err)
+	}
+}
+
+func TestPade(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		for trial := 0; trial < 16; trial++ {
+			m, n := uint(prng.Intn(6)), uint(prng.Intn(6))
+			r := randomRationalFunction(prng, field, int(m)+1, int(n)+1)
+			if r.Denominator().Coefficient(0) == 0 {
+				continue
+			}
+			s := r.Series(m + n + 1)
+			// Garbage beyond the first m+n+1 coefficients must be ignored.
+			noisy := s.Add(NewPolynomial(field, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1).Mul(
+				NewPolynomial(field, randomCoefficients(prng, int(field.Size()), 3)...)))
+			actual, err := Pade(noisy, m, n)
+			if err != nil || !actual.Equal(r) {
+				t.Errorf("%v: [%d/%d] approximant of (%v): expected (%v), got (%v), %v",
+					field, m, n, s, r, actual, err)
+			}
+		}
+	}
+}
+
+func TestPade_nonexistent(t *testing.T) {
+	// q*x = p mod x**2 with deg p = 0 forces q(0) = 0.
+	if r, err := Pade(NewPolynomial(nil, 0, 1), 0, 1); err != ErrNoPadeApproximant {
+		t.Errorf("expected ErrNoPadeApproximant, got (%v), %v", r, err)
+	}
+	if r, err := Pade(NewPolynomial(nil), 2, 2); err != nil || !r.IsZero() {
+		t.Errorf("expected the zero series to give 0, got (%v), %v", r, err)
+	}
+}
+
+func TestRationalFunction_panics(t *testing.T) {
+	type testrow struct {
+		fn       func()
+		expected error
+	}
+	one := NewRationalFunction(NewPolynomial(nil, 1), NewPolynomial(nil, 1))
+	for idx, row := range []testrow{
+		testrow{func() { NewRationalFunction(NewPolynomial(nil, 1), NewPolynomial(nil)) }, ErrDivByZero},
+		testrow{func() {
+			NewRationalFunction(NewPolynomial(nil, 1), NewPolynomial(Poly210_g2, 1))
+		}, ErrIncompatibleFields},
+		testrow{func() { one.Div(one.Add(one)) }, ErrDivByZero},
+		testrow{func() { one.Div(NewRationalFunction(NewPolynomial(nil, 0, 1), NewPolynomial(nil, 1))).Series(4) }, ErrPole},
+	} {
+		if e := panicValue(row.fn); e != row.expected {
+			t.Errorf("[%d] expected panic(%v), got %v", idx, row.expected, e)
+		}
+	}
+}
+
+// randomRationalFunction returns a rational function whose numerator has at
+// most n coefficients and whose denominator has between 1 and d.
+func randomRationalFunction(prng *rand.Rand, field *GF, n, d int) RationalFunction {
+	q := int(field.Size())
+	num := NewPolynomial(field, randomCoefficients(prng, q, prng.Intn(n+1))...)
+	den := NewPolynomial(field, randomCoefficients(prng, q, 1+prng.Intn(d))...)
+	return NewRationalFunction(num, den)
+}
@@ -0,0 +1,387 @@
+package galoisfield
+
+import (
+	"sort"
+)
+
+// Root is a root of a polynomial, together with its multiplicity.
+type Root struct {
+	Value        byte
+	Multiplicity uint
+}
+
+// Above degree 4, findRoots chooses between Berlekamp trace splitting and an
+// exhaustive Chien search.  Trace splitting costs roughly O(k * d**2 * log d)
+// for a degree-d polynomial over GF(2**k), while the Chien search costs
+// O(2**k * d).  Since the fields supported by this package are small, the
+// Chien search wins except at moderate degrees over the largest fields:
+// BenchmarkFindRoots puts the crossover at about d = 9 for GF(256), and
+// finds the Chien search faster at every degree for GF(128) and smaller.
+var (
+	chienMinDegree    uint = 9
+	traceSplitMinSize uint = 256
+)
+
+// Roots returns the distinct roots of this polynomial within its coefficient
+// field, in ascending order of value, each with its multiplicity.  The
+// multiplicities sum to the degree of the polynomial iff it splits into
+// linear factors.
+//
+// Constant polynomials have no roots.  The zero polynomial, which vanishes
+// everywhere, is also reported as having no roots.
+func (a Polynomial) Roots() []Root {
+	if a.Degree() == 0 {
+		return nil
+	}
+	field := a.field
+	f := a.coefficients
+	var roots []Root
+
+	// Factor out x**m, i.e. the root 0 with multiplicity m.
+	var m uint
+	for f[m] == 0 {
+		m++
+	}
+	if m > 0 {
+		roots = append(roots, Root{0, m})
+		f = f[m:]
+	}
+	if len(f) == 1 {
+		return roots
+	}
+
+	// g := gcd(f, x**q - x) is the product of (x - r) over the distinct
+	// roots r of f, which reduces the search to a square-free polynomial
+	// that splits completely.
+	g := wrapPolynomial(field, f)
+	xq := wrapPolynomial(field, polyXPow2Mod(field, uint(field.k), f))
+	g = g.GCD(xq.Add(NewPolynomial(field, 0, 1)))
+
+	values := findRoots(field, g.coefficients)
+	sort.Sort(byteSlice(values))
+	for _, r := range values {
+		roots = append(roots, Root{r, rootMultiplicity(field, f, r)})
+	}
+	return roots
+}
+
+// findRoots returns the roots of g, which must be monic, square-free, and a
+// product of distinct linear factors.  Degrees up to 4 are solved in closed
+// form, and higher degrees by whichever of trace splitting and the Chien
+// search is faster (see chienMinDegree).
+func findRoots(field *GF, g []byte) []byte {
+	d := uint(len(g) - 1)
+	switch {
+	case d == 0:
+		return nil
+	case d == 1:
+		return []byte{g[0]}
+	case d == 2:
+		return quadraticRoots(field, g[0], g[1])
+	case d == 3:
+		return cubicRoots(field, g[0], g[1], g[2])
+	case d == 4:
+		return quarticRoots(field, g[0], g[1], g[2], g[3])
+	case d < chienMinDegree && field.Size() >= traceSplitMinSize:
+		return traceSplitRoots(field, g)
+	default:
+		return chienSearch(field, g, d)
+	}
+}
+
+// chienSearch evaluates g at every element of the field and returns those
+// which are roots, stopping once n roots have been found.
+//
+// Rather than evaluating each term from scratch, the search tracks log_g of
+// each term g_j * α**(i*j) as i advances, so each step costs one table lookup
+// and one addition per non-zero coefficient.
+func chienSearch(field *GF, g []byte, n uint) []byte {
+	var roots []byte
+	if g[0] == 0 {
+		roots = append(roots, 0)
+	}
+	type term struct {
+		log  uint
+		step uint
+	}
+	terms := make([]term, 0, len(g))
+	for j, gj := range g {
+		if gj != 0 {
+			terms = append(terms, term{uint(field.log[gj]), uint(j) % field.m})
+		}
+	}
+	for i := uint(0); i < field.m && uint(len(roots)) < n; i++ {
+		var sum byte
+		for t := range terms {
+			sum ^= field.exp[terms[t].log]
+			terms[t].log += terms[t].step
+			if terms[t].log >= field.m {
+				terms[t].log -= field.m
+			}
+		}
+		if sum == 0 {
+			roots = append(roots, field.exp[i])
+		}
+	}
+	return roots
+}
+
+// traceSplitRoots finds the roots of g using the Berlekamp trace algorithm.
+//
+// The absolute trace Tr(y) = y + y**2 + ... + y**(2**(k-1)) is a GF(2)-linear
+// map onto {0, 1}.  For each basis element β, gcd(g, Tr(β*x) mod g) collects
+// exactly the roots r of g with Tr(β*r) = 0.  Since the map r ↦ (Tr(β_i*r))_i
+// is injective, some β always splits g into two non-trivial factors.  The
+// factors are split recursively, down to degree 4, which findRoots solves in
+// closed form.
+func traceSplitRoots(field *GF, g []byte) []byte {
+	d := len(g) - 1
+	if d <= 4 {
+		return findRoots(field, g)
+	}
+	y := make([]byte, 2*d+1)
+	tr := make([]byte, d)
+	h := make([]byte, d+1)
+	for i := byte(0); i < field.k; i++ {
+		for t := range tr {
+			tr[t] = 0
+		}
+		tr[1] = 1 << i
+		yt := y[:d]
+		copy(yt, tr)
+		for j := byte(1); j < field.k; j++ {
+			yt = frobeniusMod(field, y, yt, g)
+			for t, c := range yt {
+				tr[t] ^= c
+			}
+		}
+		copy(h, g)
+		hn := polyGCD(field, h, append(y[:0], tr...))
+		if len(hn) <= 1 || len(hn) == len(g) {
+			continue
+		}
+		q := make([]byte, len(g)-len(hn)+1)
+		polyDivMod(field, q, append([]byte(nil), g...), hn)
+		return append(
+			traceSplitRoots(field, hn),
+			traceSplitRoots(field, q)...)
+	}
+	panic("BUG: trace splitting failed; polynomial does not split")
+}
+
+// frobeniusMod squares a modulo m, using buf (which must have room for
+// 2*len(m)-1 coefficients) as scratch space, and returns the reduced result as
+// a prefix of buf.  The input a may itself be a prefix of buf.
+func frobeniusMod(field *GF, buf, a, m []byte) []byte {
+	n := len(a)
+	for i := n - 1; i >= 0; i-- {
+		ai := a[i]
+		buf[2*i+1] = 0
+		buf[2*i] = field.Mul(ai, ai)
+	}
+	sq := buf[:2*n]
+	if len(sq) >= len(m) {
+		polyDivMod(field, nil, sq, m)
+		sq = sq[:len(m)-1]
+	}
+	return sq
+}
+
+// polyGCD computes the monic GCD of a and b in place, destroying both, and
+// returns it as a prefix of one of them.
+func polyGCD(field *GF, a, b []byte) []byte {
+	a, b = reduce(a), reduce(b)
+	for len(b) > 0 {
+		if len(a) >= len(b) {
+			polyDivMod(field, nil, a, b)
+			a = reduce(a[:len(b)-1])
+		}
+		a, b = b, a
+	}
+	if len(a) > 0 {
+		inv := field.Inv(a[len(a)-1])
+		for i := range a {
+			a[i] = field.Mul(a[i], inv)
+		}
+	}
+	return a
+}
+
+// quadraticRoots returns the two roots of x**2 + b*x + c, where b != 0.
+//
+// Substituting x = b*y gives y**2 + y = e where e = c/b**2.  Given any δ with
+// Tr(δ) = 1, one solution is
+//
+//	y = ∑_{i=1}^{k-1} (∑_{j=0}^{i-1} δ**(2**j)) * e**(2**i)
+//
+// and the other is y+1.
+func quadraticRoots(field *GF, c, b byte) []byte {
+	e := field.Div(c, field.Mul(b, b))
+	delta := traceOneElement(field)
+	var y, partial byte
+	dj, ei := delta, e
+	for i := byte(1); i < field.k; i++ {
+		partial ^= dj
+		dj = field.Mul(dj, dj)
+		ei = field.Mul(ei, ei)
+		y ^= field.Mul(partial, ei)
+	}
+	return []byte{field.Mul(b, y), field.Mul(b, y^1)}
+}
+
+// cubicRoots returns the roots of x**3 + a*x**2 + b*x + c, which must split
+// into distinct linear factors.
+//
+// Multiplying by (x + a) cancels the cubic term, which leaves the affine
+// polynomial x**4 + (b + a**2)*x**2 + (c + a*b)*x + a*c.  Its roots are
+// those of the cubic, together with a, which is the sum of the three roots
+// and so is not one of them, since they are distinct.
+func cubicRoots(field *GF, c, b, a byte) []byte {
+	candidates := affineRoots(field, b^field.Mul(a, a), c^field.Mul(a, b), field.Mul(a, c))
+	roots := candidates[:0]
+	for _, x := range candidates {
+		if x != a {
+			roots = append(roots, x)
+		}
+	}
+	return roots
+}
+
+// quarticRoots returns the roots of x**4 + a*x**3 + b*x**2 + c*x + d, which
+// must split into distinct linear factors.
+//
+// Without a cubic term, the quartic is already affine.  Otherwise, the
+// substitution x = y + s with s**2 = c/a cancels the linear term, since the
+// characteristic is 2, leaving y**4 + a*y**3 + b'*y**2 + d'.  Here d' is not
+// zero, or else y = 0 would be a double root, so the reciprocal z = 1/y
+// satisfies the affine equation z**4 + (b'/d')*z**2 + (a/d')*z = 1/d'.
+func quarticRoots(field *GF, d, c, b, a byte) []byte {
+	if a == 0 {
+		return affineRoots(field, b, c, d)
+	}
+	s := field.Div(c, a)
+	for i := byte(1); i < field.k; i++ {
+		s = field.Mul(s, s)
+	}
+	h := taylorShift(field, []byte{d, c, b, a, 1}, s)
+	inv := field.Inv(h[0])
+	roots := affineRoots(field, field.Mul(h[2], inv), field.Mul(a, inv), inv)
+	for i, z := range roots {
+		roots[i] = field.Inv(z) ^ s
+	}
+	return roots
+}
+
+// affineRoots returns the distinct roots of the affine polynomial
+// x**4 + p*x**2 + q*x + r.
+//
+// L(x) = x**4 + p*x**2 + q*x is linear over GF(2), so its roots are the
+// solutions of a k×k linear system over GF(2), one bit per coefficient of
+// the field's polynomial basis: a particular solution of L(x) = r, plus any
+// element of the kernel of L.
+func affineRoots(field *GF, p, q, r byte) []byte {
+	linear := func(x byte) byte {
+		x2 := field.Mul(x, x)
+		return field.Mul(x2, x2) ^ field.Mul(p, x2) ^ field.Mul(q, x)
+	}
+	// basis[i] holds an image of L whose highest set bit is i, and
+	// preimage[i] an element which L maps to it.
+	var basis, preimage [8]byte
+	var kernel []byte
+	for j := byte(0); j < field.k; j++ {
+		image, x := linear(1<<j), byte(1)<<j
+		for i := int(field.k) - 1; i >= 0 && image != 0; i-- {
+			if image&(1<<uint(i)) == 0 {
+				continue
+			}
+			if basis[i] == 0 {
+				basis[i], preimage[i] = image, x
+				image = 0
+				x = 0
+				break
+			}
+			image ^= basis[i]
+			x ^= preimage[i]
+		}
+		if x != 0 {
+			kernel = append(kernel, x)
+		}
+	}
+	var x byte
+	for i := int(field.k) - 1; i >= 0 && r != 0; i-- {
+		if r&(1<<uint(i)) == 0 {
+			continue
+		}
+		if basis[i] == 0 {
+			return nil
+		}
+		r ^= basis[i]
+		x ^= preimage[i]
+	}
+	roots := []byte{x}
+	for _, v := range kernel {
+		for _, root := range roots {
+			roots = append(roots, root^v)
+		}
+	}
+	return roots
+}
+
+// taylorShift returns the coefficients of g(x + s), by repeated synthetic
+// division by (x + s).
+func taylorShift(field *GF, g []byte, s byte) []byte {
+	h := append([]byte(nil), g...)
+	for i := 0; i < len(h)-1; i++ {
+		for j := len(h) - 2; j >= i; j-- {
+			h[j] ^= field.Mul(s, h[j+1])
+		}
+	}
+	return h
+}
+
+// trace returns the absolute trace of y, which is always 0 or 1.
+func trace(field *GF, y byte) byte {
+	var sum byte
+	for i := byte(0); i < field.k; i++ {
+		sum ^= y
+		y = field.Mul(y, y)
+	}
+	return sum
+}
+
+// traceOneElement returns a basis element whose absolute trace is 1.
+func traceOneElement(field *GF) byte {
+	for i := byte(0); i < field.k; i++ {
+		if trace(field, 1<<i) == 1 {
+			return 1 << i
+		}
+	}
+	panic("BUG: trace is identically zero")
+}
+
+// rootMultiplicity returns the multiplicity of r as a root of f, by repeated
+// synthetic division by (x - r).
+func rootMultiplicity(field *GF, f []byte, r byte) uint {
+	q := make([]byte, len(f))
+	copy(q, f)
+	var m uint
+	for len(q) > 1 {
+		// Horner's rule: after this loop, q[0] is f(r) and q[1:] is
+		// the quotient f(x)/(x-r).
+		for i := len(q) - 2; i >= 0; i-- {
+			q[i] ^= field.Mul(q[i+1], r)
+		}
+		if q[0] != 0 {
+			break
+		}
+		q = q[1:]
+		m++
+	}
+	return m
+}
+
+type byteSlice []byte
+
+func (s byteSlice) Len() int           { return len(s) }
+func (s byteSlice) Less(i, j int) bool { return s[i] < s[j] }
+func (s byteSlice) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
@@ -0,0 +1,230 @@
+package galoisfield
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestPolynomial_Roots(t *testing.T) {
+	type testrow struct {
+		input    Polynomial
+		expected []Root
+	}
+	for idx, row := range []testrow{
+		testrow{NewPolynomial(nil), nil},
+		testrow{NewPolynomial(nil, 5), nil},
+		testrow{NewPolynomial(nil, 0, 1),
+			[]Root{Root{0, 1}}},
+		testrow{NewPolynomial(nil, 0, 0, 0, 7),
+			[]Root{Root{0, 3}}},
+		testrow{NewPolynomial(nil, 6, 1),
+			[]Root{Root{6, 1}}},
+		testrow{NewPolynomial(nil, 1, 0, 1),
+			[]Root{Root{1, 2}}},
+		testrow{NewPolynomial(nil, 6, 1, 1),
+			[]Root{Root{2, 1}, Root{3, 1}}},
+		testrow{NewPolynomial(nil, 0, 6, 1, 1),
+			[]Root{Root{0, 1}, Root{2, 1}, Root{3, 1}}},
+		testrow{NewPolynomial(Poly210_g2, 1, 1, 1),
+			[]Root{Root{2, 1}, Root{3, 1}}},
+		testrow{NewPolynomial(Poly210_g2, 2, 1, 1), nil},
+		testrow{NewPolynomial(Poly210_g2, 0, 1, 0, 0, 1),
+			[]Root{Root{0, 1}, Root{1, 1}, Root{2, 1}, Root{3, 1}}},
+	} {
+		actual := row.input.Roots()
+		if !equalRoots(actual, row.expected) {
+			t.Errorf("[%2d] expected roots of (%v) to be %v, got %v",
+				idx, row.input, row.expected, actual)
+		}
+	}
+}
+
+func TestPolynomial_Roots_random(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		n := int(field.Size())
+		for trial := 0; trial < 64; trial++ {
+			// Build a product of random linear factors, times a
+			// random cofactor which may or may not add roots.
+			p := NewPolynomial(field, byte(1+prng.Intn(n-1)))
+			for i := prng.Intn(12); i > 0; i-- {
+				r := byte(prng.Intn(n))
+				p = p.Mul(NewPolynomial(field, r, 1))
+			}
+			var cofactor []byte
+			for i := prng.Intn(4); i >= 0; i-- {
+				cofactor = append(cofactor, byte(prng.Intn(n)))
+			}
+			cofactor = append(cofactor, 1)
+			p = p.Mul(NewPolynomial(field, cofactor...))
+
+			expected := bruteForceRoots(p)
+			actual := p.Roots()
+			if !equalRoots(actual, expected) {
+				t.Errorf("expected roots of (%v) over %v to be %v, got %v",
+					p, field, expected, actual)
+			}
+		}
+	}
+}
+
+func TestFindRoots(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		n := int(field.Size())
+		for trial := 0; trial < 16; trial++ {
+			perm := prng.Perm(n)
+			d := 1 + prng.Intn(n)
+			if d > 16 {
+				d = 16
+			}
+			g := NewPolynomial(field, 1)
+			for _, r := range perm[:d] {
+				g = g.Mul(NewPolynomial(field, byte(r), 1))
+			}
+			for _, impl := range []struct {
+				name string
+				fn   func() []byte
+			}{
+				{"chienSearch", func() []byte { return chienSearch(field, g.coefficients, uint(d)) }},
+				{"traceSplitRoots", func() []byte { return traceSplitRoots(field, g.coefficients) }},
+			} {
+				actual := impl.fn()
+				if len(actual) != d {
+					t.Errorf("%s: expected %d roots of (%v) over %v, got %v",
+						impl.name, d, g, field, actual)
+					continue
+				}
+				for _, r := range actual {
+					if g.Evaluate(r) != 0 {
+						t.Errorf("%s: expected %d to be a root of (%v) over %v",
+							impl.name, r, g, field)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestClosedFormRoots(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		n := int(field.Size())
+		for _, d := range []int{3, 4} {
+			for trial := 0; trial < 64; trial++ {
+				g := NewPolynomial(field, 1)
+				for _, r := range prng.Perm(n)[:d] {
+					g = g.Mul(NewPolynomial(field, byte(r), 1))
+				}
+				c := g.coefficients
+				var actual []byte
+				if d == 3 {
+					actual = cubicRoots(field, c[0], c[1], c[2])
+				} else {
+					actual = quarticRoots(field, c[0], c[1], c[2], c[3])
+				}
+				var expected []byte
+				for x := 0; x < n; x++ {
+					if g.Evaluate(byte(x)) == 0 {
+						expected = append(expected, byte(x))
+					}
+				}
+				sort.Sort(byteSlice(actual))
+				if !equalBytes(actual, expected) {
+					t.Errorf("expected roots of (%v) over %v to be %v, got %v",
+						g, field, expected, actual)
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkPolynomial_Roots_256(b *testing.B) {
+	p := NewPolynomial(Default, 1)
+	for _, r := range []byte{3, 17, 17, 42, 99, 128, 200, 201, 255} {
+		p = p.Mul(NewPolynomial(Default, r, 1))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = p.Roots()
+	}
+}
+
+func BenchmarkPolynomial_Roots_bruteForce_256(b *testing.B) {
+	p := NewPolynomial(Default, 1)
+	for _, r := range []byte{3, 17, 17, 42, 99, 128, 200, 201, 255} {
+		p = p.Mul(NewPolynomial(Default, r, 1))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = bruteForceRoots(p)
+	}
+}
+
+// bruteForceRoots evaluates p everywhere, using Hasse derivatives to find
+// the multiplicity of each root.
+func bruteForceRoots(p Polynomial) []Root {
+	if p.Degree() == 0 {
+		return nil
+	}
+	var roots []Root
+	for x := uint(0); x < p.Field().Size(); x++ {
+		var m uint
+		for p.HasseDerivative(m).Evaluate(byte(x)) == 0 {
+			m++
+		}
+		if m > 0 {
+			roots = append(roots, Root{byte(x), m})
+		}
+	}
+	return roots
+}
+
+func equalRoots(a, b []Root) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// BenchmarkFindRoots compares the Chien search, trace splitting and the
+// closed forms on square-free polynomials which split completely, to place
+// chienMinDegree and traceSplitMinSize.
+func BenchmarkFindRoots(b *testing.B) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range []*GF{Poly410_g2, defaultFields[7], Default} {
+		for _, d := range []int{3, 4, 5, 8, 9, 12, 16} {
+			if d > int(field.Size()) {
+				continue
+			}
+			g := NewPolynomial(field, 1)
+			for _, r := range prng.Perm(int(field.Size()))[:d] {
+				g = g.Mul(NewPolynomial(field, byte(r), 1))
+			}
+			b.Run(fmt.Sprintf("GF%d/d=%d/chienSearch", field.Size(), d), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					chienSearch(field, g.coefficients, uint(d))
+				}
+			})
+			b.Run(fmt.Sprintf("GF%d/d=%d/traceSplitRoots", field.Size(), d), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					traceSplitRoots(field, g.coefficients)
+				}
+			})
+			if d <= 4 {
+				b.Run(fmt.Sprintf("GF%d/d=%d/closedForm", field.Size(), d), func(b *testing.B) {
+					for i := 0; i < b.N; i++ {
+						findRoots(field, g.coefficients)
+					}
+				})
+			}
+		}
+	}
+}
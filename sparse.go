@@ -0,0 +1,384 @@
+package galoisfield
+
+import (
+	"bytes"
+	"fmt"
+	"math/bits"
+	"sort"
+	"strconv"
+)
+
+// Term is a single non-zero term c*x**d of a sparse polynomial.
+type Term struct {
+	Degree      uint
+	Coefficient byte
+}
+
+// SparsePolynomial implements polynomials with coefficients drawn from a
+// Galois field, storing only the non-zero terms.  It suits polynomials of
+// high degree with few terms, such as the generators of cyclic codes, CRC
+// polynomials, and trinomial or pentanomial moduli, for which the dense
+// Polynomial would mostly store zeros.
+//
+// Like Polynomial, SparsePolynomial is an immutable value type.
+type SparsePolynomial struct {
+	field *GF
+	terms []Term // ascending by degree, with non-zero coefficients
+}
+
+// NewSparsePolynomial returns a new sparse polynomial with the given terms,
+// in any order.  Terms of equal degree are added together, and zero terms
+// are dropped.  If field is nil, Default is used.
+func NewSparsePolynomial(field *GF, terms ...Term) SparsePolynomial {
+	if field == nil {
+		field = Default
+	}
+	sorted := append([]Term(nil), terms...)
+	sort.Sort(byDegree(sorted))
+	return SparsePolynomial{field, combineTerms(sorted)}
+}
+
+// Sparse returns the sparse representation of this polynomial.
+func (a Polynomial) Sparse() SparsePolynomial {
+	var terms []Term
+	for d, c := range a.coefficients {
+		if c != 0 {
+			terms = append(terms, Term{uint(d), c})
+		}
+	}
+	return SparsePolynomial{a.field, terms}
+}
+
+// Dense returns the dense representation of this polynomial.  Beware that
+// this allocates Degree()+1 bytes.
+func (s SparsePolynomial) Dense() Polynomial {
+	if s.IsZero() {
+		return Polynomial{s.field, nil}
+	}
+	coefficients := make([]byte, s.Degree()+1)
+	for _, t := range s.terms {
+		coefficients[t.Degree] = t.Coefficient
+	}
+	return wrapPolynomial(s.field, coefficients)
+}
+
+// Field returns the Galois field from which this polynomial's coefficients
+// are drawn.
+func (s SparsePolynomial) Field() *GF { return s.field }
+
+// IsZero returns true iff this polynomial has no terms.
+func (s SparsePolynomial) IsZero() bool { return len(s.terms) == 0 }
+
+// Degree returns the degree of this polynomial, with the convention that the
+// polynomial of zero terms has degree 0.
+func (s SparsePolynomial) Degree() uint {
+	if s.IsZero() {
+		return 0
+	}
+	return s.terms[len(s.terms)-1].Degree
+}
+
+// Terms returns the non-zero terms of this polynomial, in ascending order of
+// degree.  The result is a copy.
+func (s SparsePolynomial) Terms() []Term {
+	return append([]Term(nil), s.terms...)
+}
+
+// Coefficient returns the coefficient of the i'th term.
+func (s SparsePolynomial) Coefficient(i uint) byte {
+	j := sort.Search(len(s.terms), func(j int) bool { return s.terms[j].Degree >= i })
+	if j < len(s.terms) && s.terms[j].Degree == i {
+		return s.terms[j].Coefficient
+	}
+	return 0
+}
+
+// Add returns the sum of one or more sparse polynomials.
+func (first SparsePolynomial) Add(rest ...SparsePolynomial) SparsePolynomial {
+	sum := first.terms
+	for _, next := range rest {
+		if first.field != next.field {
+			panic(ErrIncompatibleFields)
+		}
+		merged := make([]Term, 0, len(sum)+len(next.terms))
+		i, j := 0, 0
+		for i < len(sum) || j < len(next.terms) {
+			switch {
+			case j == len(next.terms) || i < len(sum) && sum[i].Degree < next.terms[j].Degree:
+				merged = append(merged, sum[i])
+				i++
+			case i == len(sum) || next.terms[j].Degree < sum[i].Degree:
+				merged = append(merged, next.terms[j])
+				j++
+			default:
+				if c := sum[i].Coefficient ^ next.terms[j].Coefficient; c != 0 {
+					merged = append(merged, Term{sum[i].Degree, c})
+				}
+				i++
+				j++
+			}
+		}
+		sum = merged
+	}
+	return SparsePolynomial{first.field, sum}
+}
+
+// Mul returns the product of one or more sparse polynomials.
+func (first SparsePolynomial) Mul(rest ...SparsePolynomial) SparsePolynomial {
+	field := first.field
+	prod := first.terms
+	for _, next := range rest {
+		if field != next.field {
+			panic(ErrIncompatibleFields)
+		}
+		terms := make([]Term, 0, len(prod)*len(next.terms))
+		for _, a := range prod {
+			for _, b := range next.terms {
+				terms = append(terms, Term{a.Degree + b.Degree, field.Mul(a.Coefficient, b.Coefficient)})
+			}
+		}
+		sort.Sort(byDegree(terms))
+		prod = combineTerms(terms)
+	}
+	return SparsePolynomial{field, prod}
+}
+
+// Mod returns the remainder of s divided by m.  It panics with ErrDivByZero
+// if m is the zero polynomial.
+//
+// The cost depends on the number of terms w of m rather than its degree n.
+// When the degree of s is moderate, the terms are reduced together in a
+// dense buffer, at a cost of w operations per degree.  Otherwise each term
+// c*x**d is reduced on its own, by computing x**d mod m with O(log d)
+// Frobenius squarings, each followed by a sparse reduction costing O(n*w).
+// For example, x**100000 mod a trinomial takes 17 squarings.
+func (s SparsePolynomial) Mod(m SparsePolynomial) SparsePolynomial {
+	if s.field != m.field {
+		panic(ErrIncompatibleFields)
+	}
+	if m.IsZero() {
+		panic(ErrDivByZero)
+	}
+	field := s.field
+	n := m.Degree()
+	if s.IsZero() || s.Degree() < n {
+		return s
+	}
+	if n == 0 {
+		return SparsePolynomial{field, nil}
+	}
+
+	d := s.Degree()
+	var rem []byte
+	powerCost := uint64(len(s.terms)) * uint64(n) * uint64(bits.Len(d))
+	if uint64(d) <= powerCost {
+		rem = make([]byte, d+1)
+		for _, t := range s.terms {
+			rem[t.Degree] = t.Coefficient
+		}
+		rem = sparseReduce(field, rem, m)
+	} else {
+		rem = make([]byte, n)
+		buf := make([]byte, 2*n+1)
+		for _, t := range s.terms {
+			if t.Degree < n {
+				rem[t.Degree] ^= t.Coefficient
+				continue
+			}
+			xd := sparseXPowMod(field, buf, t.Degree, m)
+			addScaled(field, rem, xd, t.Coefficient)
+		}
+	}
+	return wrapPolynomial(field, rem).Sparse()
+}
+
+// sparseReduce reduces the dense polynomial a modulo the sparse polynomial m
+// in place, and returns the remainder as a prefix of a, with length Degree(m).
+func sparseReduce(field *GF, a []byte, m SparsePolynomial) []byte {
+	n := m.Degree()
+	if uint(len(a)) <= n {
+		return a
+	}
+	lead := m.terms[len(m.terms)-1].Coefficient
+	lower := m.terms[:len(m.terms)-1]
+	inv := field.Inv(lead)
+	for i := uint(len(a)) - 1; i >= n; i-- {
+		c := a[i]
+		if c == 0 {
+			continue
+		}
+		a[i] = 0
+		c = field.Mul(c, inv)
+		for _, t := range lower {
+			a[i-n+t.Degree] ^= field.Mul(c, t.Coefficient)
+		}
+	}
+	return a[:n]
+}
+
+// sparseXPowMod returns x**d mod m, where Degree(m) = n > 0, by
+// left-to-right square-and-multiply.  buf must have room for 2n+1
+// coefficients; the result is a prefix of it, with length n.
+func sparseXPowMod(field *GF, buf []byte, d uint, m SparsePolynomial) []byte {
+	n := m.Degree()
+	r := buf[:n]
+	for i := range r {
+		r[i] = 0
+	}
+	r[0] = 1
+	for bit := highBit(d); bit > 0; bit >>= 1 {
+		// Square in place, from the top down so that no coefficient
+		// is overwritten before it is read.
+		sq := buf[:2*n-1]
+		for i := int(n) - 1; i >= 0; i-- {
+			c := sq[i]
+			sq[2*i] = field.Mul(c, c)
+			if i > 0 {
+				sq[2*i-1] = 0
+			}
+		}
+		r = sparseReduce(field, sq, m)
+		if d&bit != 0 {
+			shifted := buf[:n+1]
+			copy(shifted[1:], r)
+			shifted[0] = 0
+			r = sparseReduce(field, shifted, m)
+		}
+	}
+	return r
+}
+
+// Evaluate substitutes for x and returns the resulting value.
+func (s SparsePolynomial) Evaluate(x byte) byte {
+	field := s.field
+	if x == 0 {
+		return s.Coefficient(0)
+	}
+	logx := uint64(field.log[x])
+	var sum byte
+	for _, t := range s.terms {
+		e := (uint64(field.log[t.Coefficient]) + logx*uint64(t.Degree%field.m)) % uint64(field.m)
+		sum ^= field.exp[e]
+	}
+	return sum
+}
+
+// Compare orders sparse polynomials the same way as Polynomial.Compare: -1 if
+// s < t, 0 if s == t, +1 if s > t, or panic if s and t are drawn from
+// different Galois fields.
+func (s SparsePolynomial) Compare(t SparsePolynomial) int {
+	if cmp := s.field.Compare(t.field); cmp != 0 {
+		return cmp
+	}
+	// Walk both term lists down from the leading terms.  A term of s whose
+	// degree t lacks is compared against a zero coefficient, and vice versa.
+	i, j := len(s.terms)-1, len(t.terms)-1
+	for i >= 0 && j >= 0 {
+		si, tj := s.terms[i], t.terms[j]
+		switch {
+		case si.Degree > tj.Degree:
+			return 1
+		case si.Degree < tj.Degree:
+			return -1
+		case si.Coefficient > tj.Coefficient:
+			return 1
+		case si.Coefficient < tj.Coefficient:
+			return -1
+		}
+		i--
+		j--
+	}
+	switch {
+	case i >= 0:
+		return 1
+	case j >= 0:
+		return -1
+	}
+	return 0
+}
+
+// Less returns true iff s < t.
+func (s SparsePolynomial) Less(t SparsePolynomial) bool {
+	return s.Compare(t) < 0
+}
+
+// Equal returns true iff s == t.
+func (s SparsePolynomial) Equal(t SparsePolynomial) bool {
+	if s.field != t.field || len(s.terms) != len(t.terms) {
+		return false
+	}
+	for i := range s.terms {
+		if s.terms[i] != t.terms[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// GoString returns a Go-syntax representation of this polynomial.
+func (s SparsePolynomial) GoString() string {
+	var buf bytes.Buffer
+	buf.WriteString("NewSparsePolynomial(")
+	buf.WriteString(s.field.GoString())
+	for _, t := range s.terms {
+		buf.WriteString(", Term{")
+		buf.WriteString(strconv.FormatUint(uint64(t.Degree), 10))
+		buf.WriteString(", ")
+		buf.WriteString(strconv.Itoa(int(t.Coefficient)))
+		buf.WriteByte('}')
+	}
+	buf.WriteByte(')')
+	return buf.String()
+}
+
+// String returns a human-readable algebraic representation of this
+// polynomial, in the same format as Polynomial.String.
+func (s SparsePolynomial) String() string {
+	if s.IsZero() {
+		return "0"
+	}
+	var buf bytes.Buffer
+	for i := len(s.terms) - 1; i >= 0; i-- {
+		k, d := s.terms[i].Coefficient, s.terms[i].Degree
+		if buf.Len() > 0 {
+			buf.WriteString(" + ")
+		}
+		if k > 1 || d == 0 {
+			fmt.Fprintf(&buf, "%d", k)
+		}
+		if d > 1 {
+			fmt.Fprintf(&buf, "x^%d", d)
+		} else if d == 1 {
+			buf.WriteByte('x')
+		}
+	}
+	return buf.String()
+}
+
+// combineTerms adds together adjacent terms of equal degree in a sorted
+// slice, and drops zero terms.  It works in place.
+func combineTerms(terms []Term) []Term {
+	out := terms[:0]
+	for _, t := range terms {
+		if len(out) > 0 && out[len(out)-1].Degree == t.Degree {
+			out[len(out)-1].Coefficient ^= t.Coefficient
+			if out[len(out)-1].Coefficient == 0 {
+				out = out[:len(out)-1]
+			}
+			continue
+		}
+		if t.Coefficient != 0 {
+			out = append(out, t)
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+type byDegree []Term
+
+func (s byDegree) Len() int           { return len(s) }
+func (s byDegree) Less(i, j int) bool { return s[i].Degree < s[j].Degree }
+func (s byDegree) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
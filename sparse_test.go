@@ -0,0 +1,216 @@
+package galoisfield
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func TestNewSparsePolynomial(t *testing.T) {
+	type testrow struct {
+		terms    []Term
+		expected string
+		degree   uint
+	}
+	for idx, row := range []testrow{
+		testrow{nil, "0", 0},
+		testrow{[]Term{{0, 0}}, "0", 0},
+		testrow{[]Term{{3, 1}, {3, 1}}, "0", 0},
+		testrow{[]Term{{0, 1}, {100000, 1}, {7, 1}}, "x^100000 + x^7 + 1", 100000},
+		testrow{[]Term{{1, 5}, {1, 3}, {0, 2}}, "6x + 2", 1},
+	} {
+		p := NewSparsePolynomial(nil, row.terms...)
+		if str := p.String(); str != row.expected {
+			t.Errorf("[%d] expected %q, got %q", idx, row.expected, str)
+		}
+		if p.Degree() != row.degree {
+			t.Errorf("[%d] expected degree %d, got %d", idx, row.degree, p.Degree())
+		}
+	}
+}
+
+func TestSparsePolynomial_GoString(t *testing.T) {
+	p := NewSparsePolynomial(nil, Term{9, 2}, Term{0, 1})
+	expected := "NewSparsePolynomial(" + Default.GoString() + ", Term{0, 1}, Term{9, 2})"
+	if actual := p.GoString(); actual != expected {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+}
+
+func TestSparsePolynomial_dense(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		q := int(field.Size())
+		for trial := 0; trial < 32; trial++ {
+			a := NewPolynomial(field, randomSparseCoefficients(prng, q, prng.Intn(60))...)
+			b := NewPolynomial(field, randomSparseCoefficients(prng, q, prng.Intn(60))...)
+			m := NewPolynomial(field, randomSparseCoefficients(prng, q, 1+prng.Intn(30))...)
+			sa, sb, sm := a.Sparse(), b.Sparse(), m.Sparse()
+
+			if !sa.Dense().Equal(a) {
+				t.Errorf("%v: round trip of (%v) gave (%v)", field, a, sa.Dense())
+			}
+			if sa.String() != a.String() {
+				t.Errorf("%v: expected String %q, got %q", field, a.String(), sa.String())
+			}
+			check := func(name string, expected Polynomial, actual SparsePolynomial) {
+				if !actual.Equal(expected.Sparse()) {
+					t.Errorf("%v: %s of (%v), (%v): expected (%v), got (%v)",
+						field, name, a, b, expected, actual)
+				}
+			}
+			check("Add", a.Add(b), sa.Add(sb))
+			check("Mul", a.Mul(b), sa.Mul(sb))
+			check("Mod", a.Mul(b).Mod(m), sa.Mul(sb).Mod(sm))
+			for i := uint(0); i < 64; i++ {
+				if sa.Coefficient(i) != a.Coefficient(i) {
+					t.Errorf("%v: Coefficient(%d) of (%v): expected %d, got %d",
+						field, i, a, a.Coefficient(i), sa.Coefficient(i))
+				}
+			}
+			for x := 0; x < q; x++ {
+				if sa.Evaluate(byte(x)) != a.Evaluate(byte(x)) {
+					t.Errorf("%v: Evaluate(%d) of (%v): expected %d, got %d",
+						field, x, a, a.Evaluate(byte(x)), sa.Evaluate(byte(x)))
+				}
+			}
+		}
+	}
+}
+
+func TestSparsePolynomial_Compare(t *testing.T) {
+	type testrow struct {
+		a, b     []Term
+		expected int
+	}
+	for idx, row := range []testrow{
+		testrow{nil, nil, 0},
+		testrow{nil, []Term{{0, 1}}, -1},
+		testrow{[]Term{{5, 1}}, []Term{{4, 9}, {0, 3}}, 1},
+		testrow{[]Term{{5, 1}, {2, 1}}, []Term{{5, 1}, {3, 1}}, -1},
+		testrow{[]Term{{5, 1}, {3, 2}}, []Term{{5, 1}, {3, 1}}, 1},
+		testrow{[]Term{{5, 1}, {3, 1}, {0, 1}}, []Term{{5, 1}, {3, 1}}, 1},
+		testrow{[]Term{{7, 2}, {1, 1}}, []Term{{7, 2}, {1, 1}}, 0},
+	} {
+		a := NewSparsePolynomial(nil, row.a...)
+		b := NewSparsePolynomial(nil, row.b...)
+		if cmp := a.Compare(b); cmp != row.expected {
+			t.Errorf("[%d] expected (%v).Compare(%v) = %d, got %d", idx, a, b, row.expected, cmp)
+		}
+		if cmp := b.Compare(a); cmp != -row.expected {
+			t.Errorf("[%d] expected (%v).Compare(%v) = %d, got %d", idx, b, a, -row.expected, cmp)
+		}
+		if lt := a.Less(b); lt != (row.expected < 0) {
+			t.Errorf("[%d] expected (%v).Less(%v) = %v, got %v", idx, a, b, row.expected < 0, lt)
+		}
+	}
+
+	// The order agrees with the dense one.
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		q := int(field.Size())
+		for trial := 0; trial < 64; trial++ {
+			a := NewPolynomial(field, randomSparseCoefficients(prng, q, prng.Intn(8))...)
+			b := a.Add(NewPolynomial(field, randomSparseCoefficients(prng, q, prng.Intn(8))...))
+			if expected, cmp := a.Compare(b), a.Sparse().Compare(b.Sparse()); cmp != expected {
+				t.Errorf("%v: Compare of (%v), (%v): expected %d, got %d", field, a, b, expected, cmp)
+			}
+		}
+	}
+}
+
+func TestSparsePolynomial_Mod_highDegree(t *testing.T) {
+	// Both strategies of Mod must agree with XPowMod, whichever is chosen.
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		q := int(field.Size())
+		for _, n := range []uint{1, 2, 7, 31, 127} {
+			m := NewSparsePolynomial(field,
+				Term{n, byte(1 + prng.Intn(q-1))},
+				Term{uint(prng.Intn(int(n))), byte(1 + prng.Intn(q-1))},
+				Term{0, 1})
+			for _, d := range []uint{n, 2*n + 1, 1000, 100000, 1 << 40} {
+				c := byte(1 + prng.Intn(q-1))
+				s := NewSparsePolynomial(field, Term{d, c}, Term{d / 3, 1}, Term{0, 1})
+				expected := XPowMod(new(big.Int).SetUint64(uint64(d)), m.Dense()).Scale(c).
+					Add(XPowMod(new(big.Int).SetUint64(uint64(d/3)), m.Dense())).
+					Add(NewPolynomial(field, 1).Mod(m.Dense()))
+				if actual := s.Mod(m); !actual.Equal(expected.Sparse()) {
+					t.Errorf("%v: (%v) mod (%v): expected (%v), got (%v)",
+						field, s, m, expected, actual)
+				}
+			}
+		}
+	}
+}
+
+func TestSparsePolynomial_Evaluate_highDegree(t *testing.T) {
+	// x**(q-1) = 1 for every non-zero x.
+	for _, field := range fields {
+		p := NewSparsePolynomial(field, Term{uint(field.m) * 1000003, 1})
+		for x := 1; x < int(field.Size()); x++ {
+			if y := p.Evaluate(byte(x)); y != 1 {
+				t.Errorf("%v: expected (%v)(%d) = 1, got %d", field, p, x, y)
+			}
+		}
+		if y := p.Evaluate(0); y != 0 {
+			t.Errorf("%v: expected (%v)(0) = 0, got %d", field, p, y)
+		}
+	}
+}
+
+func TestSparsePolynomial_defensiveCopies(t *testing.T) {
+	terms := []Term{{0, 1}, {5, 2}}
+	p := NewSparsePolynomial(nil, terms...)
+	terms[0].Coefficient = 9
+	p.Terms()[1].Coefficient = 9
+	if p.Coefficient(0) != 1 || p.Coefficient(5) != 2 {
+		t.Errorf("expected terms to be copied, got (%v)", p)
+	}
+}
+
+func TestSparsePolynomial_panics(t *testing.T) {
+	type testrow struct {
+		fn       func()
+		expected error
+	}
+	for idx, row := range []testrow{
+		testrow{func() {
+			NewSparsePolynomial(nil, Term{3, 1}).Mod(NewSparsePolynomial(nil))
+		}, ErrDivByZero},
+		testrow{func() {
+			NewSparsePolynomial(nil, Term{3, 1}).Add(NewSparsePolynomial(Poly210_g2, Term{3, 1}))
+		}, ErrIncompatibleFields},
+		testrow{func() {
+			NewSparsePolynomial(nil, Term{3, 1}).Mul(NewSparsePolynomial(Poly210_g2, Term{3, 1}))
+		}, ErrIncompatibleFields},
+		testrow{func() {
+			NewSparsePolynomial(nil, Term{3, 1}).Mod(NewSparsePolynomial(Poly210_g2, Term{3, 1}))
+		}, ErrIncompatibleFields},
+	} {
+		if e := panicValue(row.fn); e != row.expected {
+			t.Errorf("[%d] expected panic(%v), got %v", idx, row.expected, e)
+		}
+	}
+}
+
+// randomSparseCoefficients returns n coefficients, most of them zero, with a
+// non-zero leading coefficient.
+func randomSparseCoefficients(prng *rand.Rand, q, n int) []byte {
+	coefficients := make([]byte, n)
+	for i := range coefficients {
+		if prng.Intn(4) == 0 || i == n-1 {
+			coefficients[i] = byte(1 + prng.Intn(q-1))
+		}
+	}
+	return coefficients
+}
+
+func BenchmarkSparsePolynomial_Mod_trinomial(b *testing.B) {
+	s := NewSparsePolynomial(nil, Term{100000, 1})
+	m := NewSparsePolynomial(nil, Term{1279, 1}, Term{216, 1}, Term{0, 1})
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Mod(m)
+	}
+}
@@ -0,0 +1,974 @@
+package galoisfield
+
+import (
+	"bytes"
+	"container/heap"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+var (
+	ErrMemoryLimit = errors.New("sparse solver would exceed its memory limit")
+)
+
+// SparseEntry is a single non-zero entry of a sparse matrix.
+type SparseEntry struct {
+	Row, Col int
+	Value    byte
+}
+
+// SparseMatrix implements matrices with entries drawn from a Galois field,
+// storing only the non-zero entries, in compressed sparse row (CSR) form.  It
+// suits the large, mostly-zero systems of fountain and LDPC codes, whose
+// dense form would be too big to store, let alone eliminate.
+//
+// Like Matrix, SparseMatrix is an immutable value type, with the same panics.
+type SparseMatrix struct {
+	field      *GF
+	rows, cols int
+	rowStart   []int  // the entries of row i are at rowStart[i]:rowStart[i+1]
+	colIndex   []int  // ascending within each row
+	values     []byte // non-zero
+}
+
+// NewSparseMatrix returns a new rows×cols sparse matrix with the given
+// entries, in any order.  Entries at the same position are added together,
+// and zero entries are dropped.  If field is nil, Default is used.  It panics
+// with ErrDimensionMismatch if an entry lies outside the matrix.
+func NewSparseMatrix(field *GF, rows, cols int, entries ...SparseEntry) SparseMatrix {
+	if field == nil {
+		field = Default
+	}
+	if rows < 0 || cols < 0 {
+		panic(ErrDimensionMismatch)
+	}
+	sorted := append([]SparseEntry(nil), entries...)
+	for _, e := range sorted {
+		if e.Row < 0 || e.Row >= rows || e.Col < 0 || e.Col >= cols {
+			panic(ErrDimensionMismatch)
+		}
+	}
+	sort.Sort(byPosition(sorted))
+	s := SparseMatrix{field, rows, cols, make([]int, rows+1), nil, nil}
+	for _, e := range sorted {
+		n := len(s.values)
+		if n > 0 && s.colIndex[n-1] == e.Col && s.rowStart[e.Row+1] > 0 {
+			s.values[n-1] ^= e.Value
+			if s.values[n-1] == 0 {
+				s.colIndex, s.values = s.colIndex[:n-1], s.values[:n-1]
+				s.rowStart[e.Row+1]--
+			}
+			continue
+		}
+		if e.Value != 0 {
+			s.colIndex = append(s.colIndex, e.Col)
+			s.values = append(s.values, e.Value)
+			s.rowStart[e.Row+1]++
+		}
+	}
+	for i := 0; i < rows; i++ {
+		s.rowStart[i+1] += s.rowStart[i]
+	}
+	return s
+}
+
+// Sparse returns the sparse representation of this matrix.
+func (m Matrix) Sparse() SparseMatrix {
+	s := SparseMatrix{m.field, m.rows, m.cols, make([]int, m.rows+1), nil, nil}
+	for i := 0; i < m.rows; i++ {
+		for j, v := range m.row(i) {
+			if v != 0 {
+				s.colIndex = append(s.colIndex, j)
+				s.values = append(s.values, v)
+			}
+		}
+		s.rowStart[i+1] = len(s.values)
+	}
+	return s
+}
+
+// Dense returns the dense representation of this matrix.  Beware that this
+// allocates Rows()*Cols() bytes.
+func (s SparseMatrix) Dense() Matrix {
+	m := NewMatrix(s.field, s.rows, s.cols)
+	for i := 0; i < s.rows; i++ {
+		for k := s.rowStart[i]; k < s.rowStart[i+1]; k++ {
+			m.data[i*s.cols+s.colIndex[k]] = s.values[k]
+		}
+	}
+	return m
+}
+
+// Field returns the Galois field from which this matrix's entries are drawn.
+func (s SparseMatrix) Field() *GF { return s.field }
+
+// Rows returns the number of rows.
+func (s SparseMatrix) Rows() int { return s.rows }
+
+// Cols returns the number of columns.
+func (s SparseMatrix) Cols() int { return s.cols }
+
+// NumEntries returns the number of non-zero entries.
+func (s SparseMatrix) NumEntries() int { return len(s.values) }
+
+// At returns the entry in row i and column j.
+func (s SparseMatrix) At(i, j int) byte {
+	if i < 0 || i >= s.rows || j < 0 || j >= s.cols {
+		panic(ErrDimensionMismatch)
+	}
+	return s.lookup(i, j)
+}
+
+// Entries returns the non-zero entries of this matrix, in row-major order.
+// The result is a copy.
+func (s SparseMatrix) Entries() []SparseEntry {
+	entries := make([]SparseEntry, 0, len(s.values))
+	for i := 0; i < s.rows; i++ {
+		for k := s.rowStart[i]; k < s.rowStart[i+1]; k++ {
+			entries = append(entries, SparseEntry{i, s.colIndex[k], s.values[k]})
+		}
+	}
+	return entries
+}
+
+// MulVec returns the matrix-vector product s*v.
+func (s SparseMatrix) MulVec(v []byte) []byte {
+	if len(v) != s.cols {
+		panic(ErrDimensionMismatch)
+	}
+	out := make([]byte, s.rows)
+	s.mulVec(out, v)
+	return out
+}
+
+// Transpose returns the transpose of this matrix.
+func (s SparseMatrix) Transpose() SparseMatrix {
+	t := SparseMatrix{s.field, s.cols, s.rows, make([]int, s.cols+1),
+		make([]int, len(s.values)), make([]byte, len(s.values))}
+	for _, j := range s.colIndex {
+		t.rowStart[j+1]++
+	}
+	for j := 0; j < s.cols; j++ {
+		t.rowStart[j+1] += t.rowStart[j]
+	}
+	next := append([]int(nil), t.rowStart[:s.cols]...)
+	for i := 0; i < s.rows; i++ {
+		for k := s.rowStart[i]; k < s.rowStart[i+1]; k++ {
+			j := s.colIndex[k]
+			t.colIndex[next[j]] = i
+			t.values[next[j]] = s.values[k]
+			next[j]++
+		}
+	}
+	return t
+}
+
+// Solve returns the solution x of s*x = b for a square matrix s, or
+// ErrSingular if s is singular, exactly as Matrix.Solve does.  It panics
+// with ErrDimensionMismatch if s is not square or b has the wrong length.
+// It uses a SparseSolver with the default settings.
+func (s SparseMatrix) Solve(b []byte) ([]byte, error) {
+	var solver SparseSolver
+	return solver.Solve(s, b)
+}
+
+// Equal returns true iff a == b.
+func (a SparseMatrix) Equal(b SparseMatrix) bool {
+	if a.field != b.field || a.rows != b.rows || a.cols != b.cols || len(a.values) != len(b.values) {
+		return false
+	}
+	for i, v := range a.rowStart {
+		if b.rowStart[i] != v {
+			return false
+		}
+	}
+	for k, j := range a.colIndex {
+		if b.colIndex[k] != j || b.values[k] != a.values[k] {
+			return false
+		}
+	}
+	return true
+}
+
+// GoString returns a Go-syntax representation of this matrix.
+func (s SparseMatrix) GoString() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "NewSparseMatrix(%s, %d, %d", s.field.GoString(), s.rows, s.cols)
+	for _, e := range s.Entries() {
+		fmt.Fprintf(&buf, ", SparseEntry{%d, %d, %d}", e.Row, e.Col, e.Value)
+	}
+	buf.WriteByte(')')
+	return buf.String()
+}
+
+// String returns a human-readable representation of this matrix, listing
+// the non-zero entries as row,col:value, such as "{0,0:1 1,2:7}".
+func (s SparseMatrix) String() string {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, e := range s.Entries() {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+		fmt.Fprintf(&buf, "%d,%d:%d", e.Row, e.Col, e.Value)
+	}
+	buf.WriteByte('}')
+	return buf.String()
+}
+
+// SparseMethod selects the algorithm used by a SparseSolver.
+type SparseMethod int
+
+const (
+	// SparseAuto uses structured Gaussian elimination, falling back to
+	// Wiedemann's algorithm if elimination would exceed the memory limit.
+	SparseAuto SparseMethod = iota
+
+	// SparseElimination uses structured Gaussian elimination only.
+	SparseElimination
+
+	// SparseWiedemann uses Wiedemann's algorithm only.
+	SparseWiedemann
+)
+
+// String returns the name of this method.
+func (m SparseMethod) String() string {
+	switch m {
+	case SparseAuto:
+		return "Auto"
+	case SparseElimination:
+		return "Elimination"
+	case SparseWiedemann:
+		return "Wiedemann"
+	}
+	return fmt.Sprintf("SparseMethod(%d)", int(m))
+}
+
+// SparseSolver solves square sparse systems s*x = b.  The zero value is
+// ready to use, and selects SparseAuto with no memory limit.
+//
+// Structured Gaussian elimination, also called inactivation decoding,
+// pivots on rows with a single active column, which creates no fill-in.
+// When every remaining row has at least two active columns, it picks a row
+// of least degree and inactivates all but one of its columns, setting them
+// aside.  Once every column is pivoted or inactive, the rows left over form
+// a dense system in the inactive columns, which is solved by Matrix.Solve
+// before back-substituting into the pivots.  For the systems of fountain and
+// LDPC codes, the inactive columns are a small fraction of the total, so the
+// cost is close to linear in the number of entries.
+//
+// Wiedemann's algorithm needs only O(n) memory besides the matrix.  This is
+// the block form, which multiplies a block y of four vectors, the first of
+// them b, by s together, and projects the sequence s**i*y onto four random
+// vectors.  A matrix form of BerlekampMassey finds a generator of the
+// projected sequence, from which x follows, in about 3n/4 block products in
+// all.  Against the scalar form, which projects b alone onto one vector,
+// the matrix is read a quarter as often but the generator costs about four
+// times as much, so the block form is faster unless s has only a few entries
+// per row.  It is a Monte Carlo
+// algorithm: a solution it returns is always correct, but to report
+// ErrSingular as Matrix.Solve would it must certify that s is non-singular,
+// by finding that the Krylov space of a block is the whole space and the
+// generator's constant term is non-singular, repeating with random blocks if
+// need be.  It may miss a singular matrix with probability less than 2**-64;
+// the random source is fixed, so the result is reproducible.
+type SparseSolver struct {
+	Method SparseMethod
+
+	// MaxMemory bounds the working memory of elimination, in bytes: the
+	// inactive parts of the rows, and the dense system.  If elimination
+	// would exceed it, SparseAuto falls back to Wiedemann's algorithm,
+	// and SparseElimination returns ErrMemoryLimit.  Zero means no limit.
+	MaxMemory int
+
+	// Progress, if not nil, is called as the solver works, with an
+	// estimate of the work done and the total.  Elimination counts rows,
+	// and Wiedemann's algorithm counts matrix-vector products, four to a
+	// block product.  The work done never decreases: if SparseAuto falls
+	// back to Wiedemann's algorithm, the count continues from the rows
+	// already eliminated, and the total grows to include the matrix-vector
+	// products.
+	Progress func(done, total int)
+}
+
+// Solve returns the solution x of s*x = b for a square matrix s, or
+// ErrSingular if s is singular, or ErrMemoryLimit as described for
+// MaxMemory.  It panics with ErrDimensionMismatch if s is not square or b has
+// the wrong length.
+func (ss *SparseSolver) Solve(s SparseMatrix, b []byte) ([]byte, error) {
+	if s.rows != s.cols || len(b) != s.rows {
+		panic(ErrDimensionMismatch)
+	}
+	rows := 0
+	if ss.Method != SparseWiedemann {
+		var x []byte
+		var err error
+		x, rows, err = ss.eliminate(s, b)
+		if err != ErrMemoryLimit || ss.Method == SparseElimination {
+			return x, err
+		}
+	}
+	return ss.wiedemann(s, b, rows)
+}
+
+// eliminate solves s*x = b by structured Gaussian elimination.  It also
+// returns the number of rows processed, which is the work reported to
+// Progress.
+//
+// Because each pivot row has a single active column, eliminating that
+// column from the other rows changes only their inactive parts, so the
+// active entries are read from s throughout and only the inactive parts,
+// dense vectors indexed by order of inactivation, are stored.
+func (ss *SparseSolver) eliminate(s SparseMatrix, b []byte) ([]byte, int, error) {
+	field := s.field
+	n := s.rows
+	t := s.Transpose() // the rows of t list the rows of s containing each column
+	const (
+		active = iota
+		pivoted
+		inactive
+	)
+	colState := make([]byte, n)
+	degree := make([]int, n) // number of active columns in each row
+	used := make([]bool, n)  // pivot rows, and rows deferred to the dense system
+	rest := make([][]byte, n)
+	rhs := append([]byte(nil), b...)
+	var inactiveCols, deferred []int
+	var pivotRows, pivotCols []int
+	memory := 0
+
+	queue := make(rowQueue, 0, n)
+	for i := 0; i < n; i++ {
+		degree[i] = s.rowStart[i+1] - s.rowStart[i]
+		queue = append(queue, rowDegree{i, degree[i]})
+	}
+	heap.Init(&queue)
+	drop := func(i int) {
+		degree[i]--
+		heap.Push(&queue, rowDegree{i, degree[i]})
+	}
+	inactivate := func(c int) error {
+		colState[c] = inactive
+		k := len(inactiveCols)
+		inactiveCols = append(inactiveCols, c)
+		for q := t.rowStart[c]; q < t.rowStart[c+1]; q++ {
+			i := t.colIndex[q]
+			if used[i] {
+				continue
+			}
+			memory += k + 1 - len(rest[i])
+			if ss.MaxMemory > 0 && memory > ss.MaxMemory {
+				return ErrMemoryLimit
+			}
+			rest[i] = extend(rest[i], k+1)
+			rest[i][k] = t.values[q]
+			drop(i)
+		}
+		return nil
+	}
+
+	for queue.Len() > 0 {
+		rd := heap.Pop(&queue).(rowDegree)
+		r := rd.row
+		if used[r] || rd.degree != degree[r] {
+			continue
+		}
+		if degree[r] == 0 {
+			used[r] = true
+			deferred = append(deferred, r)
+			ss.progress(len(pivotRows)+len(deferred), n)
+			continue
+		}
+		// Keep the active column with the most entries, since pivoting on
+		// it lowers the degree of the most rows, and inactivate the rest.
+		c := -1
+		for k := s.rowStart[r]; k < s.rowStart[r+1]; k++ {
+			if j := s.colIndex[k]; colState[j] == active && (c < 0 || t.rowLen(j) > t.rowLen(c)) {
+				c = j
+			}
+		}
+		for k := s.rowStart[r]; k < s.rowStart[r+1]; k++ {
+			if j := s.colIndex[k]; colState[j] == active && j != c {
+				if err := inactivate(j); err != nil {
+					return nil, len(pivotRows) + len(deferred), err
+				}
+			}
+		}
+		used[r] = true
+		colState[c] = pivoted
+		pivotRows, pivotCols = append(pivotRows, r), append(pivotCols, c)
+		inv := field.Inv(s.lookup(r, c))
+		for q := t.rowStart[c]; q < t.rowStart[c+1]; q++ {
+			i := t.colIndex[q]
+			if used[i] {
+				continue
+			}
+			f := field.Mul(t.values[q], inv)
+			if len(rest[i]) < len(rest[r]) {
+				memory += len(rest[r]) - len(rest[i])
+				if ss.MaxMemory > 0 && memory > ss.MaxMemory {
+					return nil, len(pivotRows) + len(deferred), ErrMemoryLimit
+				}
+				rest[i] = extend(rest[i], len(rest[r]))
+			}
+			addScaled(field, rest[i], rest[r], f)
+			rhs[i] ^= field.Mul(f, rhs[r])
+			drop(i)
+		}
+		ss.progress(len(pivotRows)+len(deferred), n)
+	}
+	// Columns which no row reached are zero; inactivating them makes the
+	// dense system singular.
+	for c := 0; c < n; c++ {
+		if colState[c] == active {
+			colState[c] = inactive
+			inactiveCols = append(inactiveCols, c)
+		}
+	}
+
+	k := len(inactiveCols)
+	if ss.MaxMemory > 0 && memory+k*k > ss.MaxMemory {
+		return nil, n, ErrMemoryLimit
+	}
+	dense := NewMatrix(field, k, k)
+	denseRHS := make([]byte, k)
+	for i, r := range deferred {
+		copy(dense.row(i), rest[r])
+		denseRHS[i] = rhs[r]
+	}
+	y, err := dense.Solve(denseRHS)
+	if err != nil {
+		return nil, n, err
+	}
+
+	x := make([]byte, n)
+	for i, c := range inactiveCols {
+		x[c] = y[i]
+	}
+	for i, r := range pivotRows {
+		v := rhs[r]
+		for j, a := range rest[r] {
+			v ^= field.Mul(a, y[j])
+		}
+		x[pivotCols[i]] = field.Div(v, s.lookup(r, pivotCols[i]))
+	}
+	return x, n, nil
+}
+
+// wiedemannBlock is the number of vectors that block Wiedemann's algorithm
+// multiplies by the matrix together, and the number onto which it projects.
+const wiedemannBlock = 4
+
+// wiedemann solves s*x = b by block Wiedemann's algorithm.  Progress is
+// reported after the given amount of work already done.
+func (ss *SparseSolver) wiedemann(s SparseMatrix, b []byte, done int) ([]byte, error) {
+	field := s.field
+	n := s.rows
+	if n == 0 {
+		return []byte{}, nil
+	}
+	nb := wiedemannBlock
+	if nb > n {
+		nb = n
+	}
+	// A run misses a singular s only if every random vector of its block
+	// lies in the image of s, with probability at most q**-(nb-1) for the
+	// first run, whose block includes b, and q**-nb for the others.
+	trials := 0
+	for bits := int(field.k) * (nb - 1); bits < 64; bits += int(field.k) * nb {
+		trials++
+	}
+	steps := (n + nb - 1) / nb
+	length := 2*steps + 4
+	// Each run takes length-1 block products for the sequence, and about
+	// steps+1 to apply the generator.
+	perRun := nb * (length + steps)
+	zeroLog := 2 * field.m
+	w := &wiedemannState{
+		s:       s,
+		prng:    rand.New(rand.NewSource(1)),
+		ss:      ss,
+		nb:      nb,
+		length:  length,
+		exp:     make([]byte, 2*zeroLog+1),
+		zeroLog: zeroLog,
+		done:    done,
+		total:   done + perRun*(trials+1),
+	}
+	copy(w.exp, field.exp)
+
+	var g *blockGenerator
+	for g == nil {
+		var err error
+		if g, err = w.run(b); err != nil {
+			return nil, err
+		}
+	}
+	// The generator's constant terms f0 are non-singular, so some
+	// combination c of its columns has f0*c = e_0, and then
+	// s*h*c = y*f0*c = b.
+	e0 := make([]byte, nb)
+	e0[0] = 1
+	c, _ := g.f0.Solve(e0)
+	x := g.combine(c)
+
+	// f0 is non-singular iff s is non-singular on the Krylov space of the
+	// block.  If that space is the whole space, s is certainly
+	// non-singular; otherwise a singular s shows up with high probability
+	// in the runs with random blocks.
+	certified := g.certified
+	for trial := 0; trial < trials && !certified; {
+		g, err := w.run(nil)
+		if err != nil {
+			return nil, err
+		}
+		if g != nil {
+			certified = g.certified
+			trial++
+		}
+	}
+	ss.progress(w.total, w.total)
+	return x, nil
+}
+
+// wiedemannState carries the state of one solution by block Wiedemann's
+// algorithm.
+type wiedemannState struct {
+	s           SparseMatrix
+	prng        *rand.Rand
+	ss          *SparseSolver
+	nb          int // block size
+	length      int // length of the projected sequence
+	done, total int
+
+	// The products of blocks with small matrices are taken in the
+	// logarithm domain, with exp and zeroLog as for mulMod.
+	exp     []byte
+	zeroLog uint
+}
+
+// blockGenerator is the outcome of one run of block Wiedemann's algorithm:
+// a matrix polynomial f(x) = ∑_k f_k*x**k whose nb columns generate the
+// sequence s**i*y of the run's block y, in that ∑_k s**k*y*f_k = 0.
+type blockGenerator struct {
+	n, nb int
+	f0    Matrix // the constant term of f
+	h     []byte // ∑_{k≥1} s**(k-1)*y*f_k, n×nb in row-major order
+
+	// certified is set if the Krylov space of y is the whole space, in
+	// which case f0 is non-singular iff s is.
+	certified bool
+}
+
+// combine returns h*c.
+func (g *blockGenerator) combine(c []byte) []byte {
+	field := g.f0.field
+	x := make([]byte, g.n)
+	for i := range x {
+		var sum byte
+		for j, v := range g.h[i*g.nb : (i+1)*g.nb] {
+			sum ^= field.Mul(v, c[j])
+		}
+		x[i] = sum
+	}
+	return x
+}
+
+// run performs one run of block Wiedemann's algorithm, with a random block
+// whose first vector is b unless b is nil.  It returns ErrSingular if it
+// finds a non-zero vector in the kernel of s, or a nil generator if the
+// random projection missed part of the sequence, in which case the run
+// should be repeated.
+//
+// Since s*h = y*f0, s is singular if f0 is: for f0*c = 0, h*c is in the
+// kernel of s unless it is zero, and then f(x)*c/x would be a generator of
+// lower degree than the run found.
+func (w *wiedemannState) run(b []byte) (*blockGenerator, error) {
+	s := w.s
+	field := s.field
+	n, nb := s.rows, w.nb
+	y := w.randomBlock()
+	if b != nil {
+		for i, v := range b {
+			y[i*nb] = v
+		}
+	}
+	ylogs := w.logsOf(y)
+	ulogs := w.logsOf(w.randomBlock())
+
+	// seq[i] is the nb×nb matrix u^T*s**i*y.
+	seq := make([][]byte, w.length)
+	vlogs := make([]uint, nb)
+	v := y
+	for i := range seq {
+		a := make([]byte, nb*nb)
+		for l := 0; l < n; l++ {
+			for c, x := range v[l*nb : (l+1)*nb] {
+				vlogs[c] = w.logOf(x)
+			}
+			for r, lu := range ulogs[l*nb : (l+1)*nb] {
+				ar := a[r*nb : (r+1)*nb]
+				for c, lv := range vlogs {
+					ar[c] ^= w.exp[lu+lv]
+				}
+			}
+		}
+		seq[i] = a
+		if i+1 < len(seq) {
+			v = w.mulBlock(v)
+		}
+	}
+	f := blockBerlekampMassey(field, seq, nb)
+
+	// Apply the generator by Horner's rule: h accumulates
+	// ∑_{k≥1} s**(k-1)*y*f_k, and then s*h + y*f_0 must vanish.
+	h := make([]byte, n*nb)
+	for k := len(f) - 1; k >= 1; k-- {
+		if k < len(f)-1 {
+			h = w.mulBlock(h)
+		}
+		w.addProduct(h, ylogs, f[k])
+	}
+	check := make([]byte, n*nb)
+	if len(f) > 1 {
+		check = w.mulBlock(h)
+	}
+	w.addProduct(check, ylogs, f[0])
+	if !isZeroVector(check) {
+		return nil, nil
+	}
+
+	g := &blockGenerator{n: n, nb: nb, f0: NewMatrix(field, nb, nb), h: h}
+	copy(g.f0.data, f[0])
+	if g.f0.Rank() < nb {
+		kernel := g.f0.Nullspace()
+		for j := 0; j < kernel.cols; j++ {
+			if !isZeroVector(g.combine(kernel.Col(j))) {
+				return nil, ErrSingular
+			}
+		}
+		return nil, nil
+	}
+	// The degree of det f is the dimension of the Krylov space, and it is
+	// the sum of the column degrees if the leading coefficients of the
+	// columns are independent.
+	lead := NewMatrix(field, nb, nb)
+	sum := 0
+	for j := 0; j < nb; j++ {
+		d := len(f) - 1
+		for d > 0 && isZeroColumn(f[d], nb, j) {
+			d--
+		}
+		sum += d
+		for r := 0; r < nb; r++ {
+			lead.data[r*nb+j] = f[d][r*nb+j]
+		}
+	}
+	g.certified = sum == n && lead.Rank() == nb
+	return g, nil
+}
+
+// randomBlock returns a random n×nb block of vectors, in row-major order.
+func (w *wiedemannState) randomBlock() []byte {
+	q := int(w.s.field.Size())
+	v := make([]byte, w.s.rows*w.nb)
+	for i := range v {
+		v[i] = byte(w.prng.Intn(q))
+	}
+	return v
+}
+
+// mulBlock returns s*v for an n×nb block v, and reports progress.  Each
+// entry of s is read once for the whole block.
+func (w *wiedemannState) mulBlock(v []byte) []byte {
+	s := w.s
+	field := s.field
+	nb := w.nb
+	out := make([]byte, len(v))
+	for i := 0; i < s.rows; i++ {
+		row := out[i*nb : (i+1)*nb]
+		for k := s.rowStart[i]; k < s.rowStart[i+1]; k++ {
+			j := s.colIndex[k]
+			addScaled(field, row, v[j*nb:(j+1)*nb], s.values[k])
+		}
+	}
+	if w.done += nb; w.done < w.total {
+		w.ss.progress(w.done, w.total)
+	}
+	return out
+}
+
+// addProduct adds y*c to h, where h is an n×nb block, c is nb×nb, and
+// ylogs holds the logarithms of the n×nb block y, all in row-major order.
+func (w *wiedemannState) addProduct(h []byte, ylogs []uint, c []byte) {
+	nb := w.nb
+	clogs := w.logsOf(c)
+	for i := 0; i < len(h); i += nb {
+		hi := h[i : i+nb]
+		for r, ly := range ylogs[i : i+nb] {
+			for j, lc := range clogs[r*nb : (r+1)*nb] {
+				hi[j] ^= w.exp[ly+lc]
+			}
+		}
+	}
+}
+
+// logsOf returns the logarithms of the elements of x, with zeroLog for zero.
+func (w *wiedemannState) logsOf(x []byte) []uint {
+	logs := make([]uint, len(x))
+	for i, v := range x {
+		logs[i] = w.logOf(v)
+	}
+	return logs
+}
+
+// logOf returns the logarithm of x, or zeroLog if x is zero.
+func (w *wiedemannState) logOf(x byte) uint {
+	if x == 0 {
+		return w.zeroLog
+	}
+	return uint(w.s.field.log[x])
+}
+
+// isZeroColumn returns true iff column j of the nb×nb matrix a is zero.
+func isZeroColumn(a []byte, nb, j int) bool {
+	for r := 0; r < nb; r++ {
+		if a[r*nb+j] != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// blockBerlekampMassey returns a generator of the sequence of nb×nb matrices
+// seq: a matrix polynomial f(x) = ∑_k f_k*x**k, as its coefficients, whose
+// columns satisfy ∑_k seq[i+k]*f_k = 0 for as many i as the length of seq
+// allows.  It is the matrix form of BerlekampMassey.
+//
+// With S(x) = ∑_i seq[i]*x**i, a column f of degree d corresponds to a pair
+// of vectors of polynomials (φ, γ), with φ(x) = x**d * f(1/x) and deg γ < d,
+// such that S*φ + γ = 0 mod x**len(seq).  These pairs are the order-len(seq)
+// approximants of the nb×2nb matrix [S I], and a basis of them is built one
+// order at a time, in the manner of the M-Basis algorithm of Giorgi,
+// Jeannerod and Villard: the columns of least degree eliminate the residual
+// of the next order from the others, and are then multiplied by x.  Column
+// degrees count the rows of γ one higher, so that the nb columns of least
+// degree have deg γ < deg φ, and these are the generator.
+func blockBerlekampMassey(field *GF, seq [][]byte, nb int) [][]byte {
+	m := 2 * nb
+	zeroLog := 2 * field.m
+	exp := make([]byte, 2*zeroLog+1)
+	copy(exp, field.exp)
+	// seqLogs[i][r*nb:(r+1)*nb] holds the logarithms of column r of seq[i].
+	seqLogs := make([][]uint, len(seq))
+	for i, a := range seq {
+		logs := make([]uint, nb*nb)
+		for r := 0; r < nb; r++ {
+			for l := 0; l < nb; l++ {
+				if v := a[l*nb+r]; v == 0 {
+					logs[r*nb+l] = zeroLog
+				} else {
+					logs[r*nb+l] = uint(field.log[v])
+				}
+			}
+		}
+		seqLogs[i] = logs
+	}
+
+	// Column c of the basis is p[c], with the m rows of its coefficient of
+	// x**t in p[c][t*m:(t+1)*m], the nb rows of φ above those of γ.
+	// degree[c] is the degree of column c.
+	p := make([][]byte, m)
+	degree := make([]int, m)
+	for c := range p {
+		p[c] = make([]byte, m)
+		p[c][c] = 1
+		if c >= nb {
+			degree[c] = 1
+		}
+	}
+	order := make([]int, m)
+	for c := range order {
+		order[c] = c
+	}
+	byDegree := func() {
+		sort.SliceStable(order, func(i, j int) bool { return degree[order[i]] < degree[order[j]] })
+	}
+	residual := make([][]byte, m)
+	for c := range residual {
+		residual[c] = make([]byte, nb)
+	}
+	pivotRow := make([]int, m)
+	combination := make([][]byte, m)
+	for k := range seq {
+		// The residual of column c is the coefficient of x**k in
+		// S*φ + γ.
+		for c, rc := range residual {
+			for i := range rc {
+				rc[i] = 0
+			}
+			pc := p[c]
+			for t := 0; t <= k && t*m < len(pc); t++ {
+				logs := seqLogs[k-t]
+				for r, v := range pc[t*m : t*m+nb] {
+					if v == 0 {
+						continue
+					}
+					lv := uint(field.log[v])
+					for i, la := range logs[r*nb : (r+1)*nb] {
+						rc[i] ^= exp[lv+la]
+					}
+				}
+			}
+			if k*m < len(pc) {
+				for i, v := range pc[k*m+nb : (k+1)*m] {
+					rc[i] ^= v
+				}
+			}
+		}
+		// Eliminate the residuals in order of degree.  A column whose
+		// residual is independent of those before it is a pivot, and is
+		// left alone; the others are reduced to zero by the pivots, which
+		// have no greater degree.  The residual of pivot c is reduced in
+		// place by the pivots before it, and combination[c] expresses the
+		// result in terms of their columns, so that only the non-pivot
+		// columns of the basis need to be updated.
+		byDegree()
+		var pivots []int
+		for _, c := range order {
+			rc := residual[c]
+			coefficients := make([]byte, len(pivots))
+			for _, pc := range pivots {
+				e := rc[pivotRow[pc]]
+				if e == 0 {
+					continue
+				}
+				f := field.Div(e, residual[pc][pivotRow[pc]])
+				addScaled(field, rc, residual[pc], f)
+				addScaled(field, coefficients, combination[pc], f)
+			}
+			pivot := false
+			for i, v := range rc {
+				if v != 0 {
+					pivot = true
+					pivotRow[c] = i
+					break
+				}
+			}
+			if pivot {
+				combination[c] = append(coefficients, 1)
+				pivots = append(pivots, c)
+				continue
+			}
+			for idx, f := range coefficients {
+				if f != 0 {
+					pc := pivots[idx]
+					p[c] = extend(p[c], len(p[pc]))
+					addScaled(field, p[c], p[pc], f)
+				}
+			}
+		}
+		for _, c := range pivots {
+			p[c] = append(make([]byte, m, len(p[c])+m), p[c]...)
+			degree[c]++
+		}
+	}
+	byDegree()
+
+	// Reverse φ in each of the nb columns of least degree.
+	var f [][]byte
+	for j, c := range order[:nb] {
+		d := 0
+		for t := 0; t*m < len(p[c]); t++ {
+			for r, v := range p[c][t*m : (t+1)*m] {
+				if v == 0 {
+					continue
+				}
+				if l := t + r/nb; l > d {
+					d = l
+				}
+			}
+		}
+		for len(f) <= d {
+			f = append(f, make([]byte, nb*nb))
+		}
+		for t := 0; t <= d && t*m < len(p[c]); t++ {
+			for r, v := range p[c][t*m : t*m+nb] {
+				f[d-t][r*nb+j] = v
+			}
+		}
+	}
+	return f
+}
+
+func (ss *SparseSolver) progress(done, total int) {
+	if ss.Progress != nil {
+		ss.Progress(done, total)
+	}
+}
+
+// mulVec stores s*v in out.
+func (s SparseMatrix) mulVec(out, v []byte) {
+	for i := 0; i < s.rows; i++ {
+		var sum byte
+		for k := s.rowStart[i]; k < s.rowStart[i+1]; k++ {
+			sum ^= s.field.Mul(s.values[k], v[s.colIndex[k]])
+		}
+		out[i] = sum
+	}
+}
+
+// lookup returns the entry in row i and column j.
+func (s SparseMatrix) lookup(i, j int) byte {
+	cols := s.colIndex[s.rowStart[i]:s.rowStart[i+1]]
+	k := sort.SearchInts(cols, j)
+	if k < len(cols) && cols[k] == j {
+		return s.values[s.rowStart[i]+k]
+	}
+	return 0
+}
+
+// rowLen returns the number of entries in row i.
+func (s SparseMatrix) rowLen(i int) int {
+	return s.rowStart[i+1] - s.rowStart[i]
+}
+
+// extend returns a, extended with zeros to length n.
+func extend(a []byte, n int) []byte {
+	if len(a) >= n {
+		return a
+	}
+	return append(a, make([]byte, n-len(a))...)
+}
+
+type byPosition []SparseEntry
+
+func (s byPosition) Len() int { return len(s) }
+func (s byPosition) Less(i, j int) bool {
+	return s[i].Row < s[j].Row || s[i].Row == s[j].Row && s[i].Col < s[j].Col
+}
+func (s byPosition) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+
+// rowDegree is an entry of a rowQueue.  Entries go stale when the degree of
+// their row changes, and are skipped when popped.
+type rowDegree struct {
+	row, degree int
+}
+
+// rowQueue is a min-heap of rows by degree.
+type rowQueue []rowDegree
+
+func (q rowQueue) Len() int { return len(q) }
+func (q rowQueue) Less(i, j int) bool {
+	return q[i].degree < q[j].degree || q[i].degree == q[j].degree && q[i].row < q[j].row
+}
+func (q rowQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *rowQueue) Push(x interface{}) { *q = append(*q, x.(rowDegree)) }
+func (q *rowQueue) Pop() interface{} {
+	old := *q
+	x := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return x
+}
@@ -0,0 +1,247 @@
+package galoisfield
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func TestSparseMatrix_basics(t *testing.T) {
+	s := NewSparseMatrix(nil, 2, 3,
+		SparseEntry{1, 2, 7}, SparseEntry{0, 0, 1}, SparseEntry{1, 2, 7},
+		SparseEntry{1, 0, 3}, SparseEntry{0, 1, 0}, SparseEntry{1, 2, 5})
+	if expected := "{0,0:1 1,0:3 1,2:5}"; s.String() != expected {
+		t.Errorf("expected %q, got %q", expected, s.String())
+	}
+	if expected := "NewSparseMatrix(Poly84320_g2, 2, 3, SparseEntry{0, 0, 1}, SparseEntry{1, 0, 3}, SparseEntry{1, 2, 5})"; s.GoString() != expected {
+		t.Errorf("expected %q, got %q", expected, s.GoString())
+	}
+	if s.NumEntries() != 3 || s.At(1, 2) != 5 || s.At(0, 2) != 0 {
+		t.Errorf("bad entries in %v", s)
+	}
+	d := s.Dense()
+	if expected := "[1 0 0; 3 0 5]"; d.String() != expected {
+		t.Errorf("expected %q, got %q", expected, d.String())
+	}
+	if !d.Sparse().Equal(s) || !s.Transpose().Equal(d.Transpose().Sparse()) {
+		t.Errorf("conversions of %v disagree", s)
+	}
+
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		m := randomSparseMatrix(prng, field, 20, 3)
+		v := randomCoefficients(prng, int(field.Size()), 20)
+		if !equalBytes(m.MulVec(v), m.Dense().MulVec(v)) {
+			t.Errorf("%v: MulVec disagrees with the dense product for %v", field, m)
+		}
+	}
+}
+
+func TestSparseMatrix_Solve(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		for trial := 0; trial < 16; trial++ {
+			n := prng.Intn(40)
+			s := randomSparseMatrix(prng, field, n, 1+prng.Intn(4))
+			b := randomCoefficients(prng, int(field.Size()), n)
+			expected, expectedErr := s.Dense().Solve(b)
+			for _, solver := range []*SparseSolver{
+				&SparseSolver{},
+				&SparseSolver{Method: SparseElimination},
+				&SparseSolver{Method: SparseWiedemann},
+				&SparseSolver{MaxMemory: 1},
+			} {
+				x, err := solver.Solve(s, b)
+				if err != expectedErr || err == nil && !equalBytes(x, expected) {
+					t.Errorf("%v: %v solving %v: expected %v, %v; got %v, %v",
+						field, solver.Method, s, expected, expectedErr, x, err)
+				}
+			}
+		}
+	}
+}
+
+func TestSparseSolver_limits(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	// A dense matrix needs many inactive columns.
+	s := randomMatrix(prng, nil, 30, 30).Sparse()
+	b := randomCoefficients(prng, 256, 30)
+	solver := &SparseSolver{Method: SparseElimination, MaxMemory: 100}
+	if _, err := solver.Solve(s, b); err != ErrMemoryLimit {
+		t.Errorf("expected %v, got %v", ErrMemoryLimit, err)
+	}
+
+	var calls, last, total int
+	solver = &SparseSolver{MaxMemory: 100, Progress: func(done, n int) {
+		if done < last || done > n {
+			t.Errorf("progress went from %d to %d of %d", last, done, n)
+		}
+		calls, last, total = calls+1, done, n
+	}}
+	x, err := solver.Solve(s, b)
+	if err != nil || !equalBytes(s.MulVec(x), b) {
+		t.Errorf("expected a solution, got %v, %v", x, err)
+	}
+	if calls == 0 || last != total {
+		t.Errorf("expected progress to reach the total, got %d calls ending at %d of %d", calls, last, total)
+	}
+}
+
+func TestSparseSolver_fallbackProgress(t *testing.T) {
+	// Elimination gets part of the way through an LDPC-like system before
+	// it runs out of memory, and Wiedemann's algorithm takes over.
+	prng := rand.New(rand.NewSource(42))
+	n := 200
+	perm := prng.Perm(n)
+	var entries []SparseEntry
+	for i := 0; i < n; i++ {
+		entries = append(entries, SparseEntry{i, perm[i], byte(1 + prng.Intn(255))})
+		for k := 0; k < 3; k++ {
+			entries = append(entries, SparseEntry{i, prng.Intn(n), byte(prng.Intn(256))})
+		}
+	}
+	s := NewSparseMatrix(nil, n, n, entries...)
+	b := randomCoefficients(prng, 256, n)
+	expected, expectedErr := s.Dense().Solve(b)
+
+	var last, eliminated, total int
+	solver := &SparseSolver{MaxMemory: 2000, Progress: func(done, n int) {
+		if done < last || done > n {
+			t.Errorf("progress went from %d to %d of %d", last, done, n)
+		}
+		if n == s.Rows() {
+			eliminated = done
+		}
+		last, total = done, n
+	}}
+	x, err := solver.Solve(s, b)
+	if err != expectedErr || !equalBytes(x, expected) {
+		t.Errorf("expected %v, %v; got %v, %v", expected, expectedErr, x, err)
+	}
+	if eliminated == 0 || total == s.Rows() {
+		t.Errorf("expected a fallback after some elimination, got %d rows and a total of %d", eliminated, total)
+	}
+}
+
+func TestSparseSolver_large(t *testing.T) {
+	// An LDPC-like system: a random permutation plus two random entries
+	// per row.
+	prng := rand.New(rand.NewSource(42))
+	n := 3000
+	perm := prng.Perm(n)
+	var entries []SparseEntry
+	for i := 0; i < n; i++ {
+		entries = append(entries, SparseEntry{i, perm[i], byte(1 + prng.Intn(255))})
+		for k := 0; k < 2; k++ {
+			entries = append(entries, SparseEntry{i, prng.Intn(n), byte(prng.Intn(256))})
+		}
+	}
+	s := NewSparseMatrix(nil, n, n, entries...)
+	b := randomCoefficients(prng, 256, n)
+	var rows int
+	solver := &SparseSolver{Method: SparseElimination, MaxMemory: 1 << 24, Progress: func(done, _ int) { rows = done }}
+	x, err := solver.Solve(s, b)
+	if err == ErrSingular {
+		t.Skip("random system is singular")
+	}
+	if err != nil || !equalBytes(s.MulVec(x), b) {
+		t.Fatalf("expected a solution, got %v", err)
+	}
+	if rows != n {
+		t.Errorf("expected progress over %d rows, got %d", n, rows)
+	}
+}
+
+func TestSparseSolver_wiedemann(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	solver := &SparseSolver{Method: SparseWiedemann}
+	for _, field := range fields {
+		q := int(field.Size())
+		for _, n := range []int{0, 1, 2, 3, 4, 5, 9, 64, 200} {
+			// Diagonal matrices have small Krylov spaces, so only the
+			// extra runs can certify them, and a single zero on the
+			// diagonal must still be found.
+			diagonal := make([]SparseEntry, n)
+			for i := range diagonal {
+				diagonal[i] = SparseEntry{i, i, byte(1 + prng.Intn(q-1))}
+			}
+			singular := append([]SparseEntry(nil), diagonal...)
+			if n > 0 {
+				singular[prng.Intn(n)].Value = 0
+			}
+			for _, s := range []SparseMatrix{
+				randomSparseMatrix(prng, field, n, 3),
+				randomSparseMatrix(prng, field, n, 1),
+				NewSparseMatrix(field, n, n, diagonal...),
+				NewSparseMatrix(field, n, n, singular...),
+				NewSparseMatrix(field, n, n),
+			} {
+				b := randomCoefficients(prng, q, n)
+				expected, expectedErr := s.Dense().Solve(b)
+				x, err := solver.Solve(s, b)
+				if err != expectedErr || err == nil && !equalBytes(x, expected) {
+					t.Errorf("%v: solving %v: expected %v, %v; got %v, %v",
+						field, s, expected, expectedErr, x, err)
+				}
+			}
+		}
+	}
+}
+
+func TestSparseMatrix_panics(t *testing.T) {
+	type testrow struct {
+		fn       func()
+		expected error
+	}
+	a := NewSparseMatrix(nil, 2, 3)
+	for idx, row := range []testrow{
+		testrow{func() { NewSparseMatrix(nil, 2, 2, SparseEntry{2, 0, 1}) }, ErrDimensionMismatch},
+		testrow{func() { NewSparseMatrix(nil, -1, 2) }, ErrDimensionMismatch},
+		testrow{func() { a.At(0, 3) }, ErrDimensionMismatch},
+		testrow{func() { a.MulVec([]byte{1}) }, ErrDimensionMismatch},
+		testrow{func() { a.Solve([]byte{1, 2}) }, ErrDimensionMismatch},
+		testrow{func() { NewSparseMatrix(nil, 2, 2).Solve([]byte{1}) }, ErrDimensionMismatch},
+	} {
+		if e := panicValue(row.fn); e != row.expected {
+			t.Errorf("[%d] expected panic(%v), got %v", idx, row.expected, e)
+		}
+	}
+}
+
+// randomSparseMatrix returns an n×n matrix with about perRow entries in each
+// row, one of which lies on a random permutation, so that singular matrices
+// are not too common.
+func randomSparseMatrix(prng *rand.Rand, field *GF, n, perRow int) SparseMatrix {
+	var entries []SparseEntry
+	for i, j := range prng.Perm(n) {
+		entries = append(entries, SparseEntry{i, j, byte(1 + prng.Intn(int(field.Size())-1))})
+		for k := 1; k < perRow; k++ {
+			entries = append(entries, SparseEntry{i, prng.Intn(n), byte(prng.Intn(int(field.Size())))})
+		}
+	}
+	return NewSparseMatrix(field, n, n, entries...)
+}
+
+func BenchmarkSparseSolver_Elimination(b *testing.B) {
+	prng := rand.New(rand.NewSource(42))
+	s := randomSparseMatrix(prng, Default, 1000, 3)
+	v := randomCoefficients(prng, 256, 1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Solve(v)
+	}
+}
+
+func BenchmarkSparseSolver_Wiedemann(b *testing.B) {
+	solver := &SparseSolver{Method: SparseWiedemann}
+	for _, perRow := range []int{3, 30} {
+		prng := rand.New(rand.NewSource(42))
+		s := randomSparseMatrix(prng, Default, 1000, perRow)
+		v := randomCoefficients(prng, 256, 1000)
+		b.Run(fmt.Sprintf("%d_per_row", perRow), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				solver.Solve(s, v)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package galoisfield
+
+// Vandermonde returns the len(xs)×cols Vandermonde matrix whose entry in row
+// i and column j is xs[i]**j.  It returns ErrDuplicatePoint if any point
+// appears more than once, or ErrPointOutOfRange if any point is not an
+// element of the field.  If field is nil, Default is used.
+//
+// Multiplying this matrix by the coefficients of a polynomial of degree less
+// than cols evaluates the polynomial at the points, which is Reed-Solomon
+// encoding.  With distinct points, every set of cols rows is invertible.
+func Vandermonde(field *GF, xs []byte, cols int) (Matrix, error) {
+	if field == nil {
+		field = Default
+	}
+	if err := checkDistinct(field, xs); err != nil {
+		return Matrix{}, err
+	}
+	m := NewMatrix(field, len(xs), cols)
+	for i, x := range xs {
+		row := m.row(i)
+		var power byte = 1
+		for j := range row {
+			row[j] = power
+			power = field.Mul(power, x)
+		}
+	}
+	return m, nil
+}
+
+// SystematicVandermonde returns the len(xs)×k generator matrix of the
+// systematic Reed-Solomon code with the given evaluation points: the
+// Vandermonde matrix V times the inverse of its top k×k block, so that the
+// top k rows form the identity and the data appear unchanged in the
+// codeword.  Any k rows remain invertible.  It returns the same errors as
+// Vandermonde, and panics with ErrDimensionMismatch if k > len(xs).
+func SystematicVandermonde(field *GF, xs []byte, k int) (Matrix, error) {
+	if k > len(xs) {
+		panic(ErrDimensionMismatch)
+	}
+	v, err := Vandermonde(field, xs, k)
+	if err != nil {
+		return Matrix{}, err
+	}
+	top, _ := VandermondeInverse(v.field, xs[:k])
+	return v.Mul(top), nil
+}
+
+// VandermondeInverse returns the inverse of the square Vandermonde matrix
+// with the given points, in O(n**2) field operations.  It returns the same
+// errors as Vandermonde.
+//
+// Column i of the inverse holds the coefficients of the Lagrange basis
+// polynomial L_i(x) = ∏_{j≠i} (x - xs[j]) / (xs[i] - xs[j]), which are found
+// by synthetic division of w(x) = ∏_j (x - xs[j]) by (x - xs[i]).
+func VandermondeInverse(field *GF, xs []byte) (Matrix, error) {
+	if field == nil {
+		field = Default
+	}
+	if err := checkDistinct(field, xs); err != nil {
+		return Matrix{}, err
+	}
+	n := len(xs)
+	w := make([]byte, n+1)
+	w[0] = 1
+	for j, xj := range xs {
+		for i := j + 1; i > 0; i-- {
+			w[i] = w[i-1] ^ field.Mul(w[i], xj)
+		}
+		w[0] = field.Mul(w[0], xj)
+	}
+
+	inv := NewMatrix(field, n, n)
+	q := make([]byte, n)
+	for i, xi := range xs {
+		var carry, denom byte
+		for d := n; d > 0; d-- {
+			carry = w[d] ^ field.Mul(carry, xi)
+			q[d-1] = carry
+			denom = field.Mul(denom, xi) ^ carry
+		}
+		s := field.Inv(denom)
+		for d, qd := range q {
+			inv.data[d*n+i] = field.Mul(s, qd)
+		}
+	}
+	return inv, nil
+}
+
+// VandermondeSolve returns the solution a of V*a = b, where V is the square
+// Vandermonde matrix with the given points; that is, the coefficients of the
+// polynomial of degree less than len(xs) taking the value b[i] at xs[i].  It
+// returns ErrLengthMismatch if xs and b have different lengths, or the same
+// errors as Vandermonde.
+//
+// This is the Björck-Pereyra algorithm, which takes O(n**2) field operations
+// and O(n) space: Newton's divided differences, followed by conversion from
+// the Newton basis to the monomial basis.
+func VandermondeSolve(field *GF, xs, b []byte) ([]byte, error) {
+	if field == nil {
+		field = Default
+	}
+	if len(xs) != len(b) {
+		return nil, ErrLengthMismatch
+	}
+	if err := checkDistinct(field, xs); err != nil {
+		return nil, err
+	}
+	n := len(xs)
+	a := append([]byte(nil), b...)
+	for k := 0; k < n-1; k++ {
+		for i := n - 1; i > k; i-- {
+			a[i] = field.Div(a[i]^a[i-1], xs[i]^xs[i-k-1])
+		}
+	}
+	for k := n - 2; k >= 0; k-- {
+		for i := k; i < n-1; i++ {
+			a[i] ^= field.Mul(xs[k], a[i+1])
+		}
+	}
+	return a, nil
+}
+
+// Cauchy returns the len(xs)×len(ys) Cauchy matrix whose entry in row i and
+// column j is 1/(xs[i] + ys[j]).  Every square sub-matrix of a Cauchy matrix
+// is invertible, which makes it a convenient MDS generator for erasure codes.
+// It returns ErrDuplicatePoint unless all of the xs and ys together are
+// distinct, or ErrPointOutOfRange if any is not an element of the field.  If
+// field is nil, Default is used.
+func Cauchy(field *GF, xs, ys []byte) (Matrix, error) {
+	if field == nil {
+		field = Default
+	}
+	if err := checkDistinct(field, append(append([]byte(nil), xs...), ys...)); err != nil {
+		return Matrix{}, err
+	}
+	m := NewMatrix(field, len(xs), len(ys))
+	for i, x := range xs {
+		row := m.row(i)
+		for j, y := range ys {
+			row[j] = field.Inv(x ^ y)
+		}
+	}
+	return m, nil
+}
+
+// CauchyInverse returns the inverse of the square Cauchy matrix with the
+// given points, in O(n**2) field operations, using the closed form
+//
+//	B[i][j] = a(xs[j]) b(ys[i]) / ((xs[j] + ys[i]) a'(ys[i]) b'(xs[j]))
+//
+// where a(t) = ∏_k (t + ys[k]) and b(t) = ∏_k (t + xs[k]), and a' and b'
+// are their derivatives (so that b'(xs[j]) = ∏_{k≠j} (xs[j] + xs[k])).  It
+// returns ErrLengthMismatch if xs and ys have different lengths, or the
+// same errors as Cauchy.
+func CauchyInverse(field *GF, xs, ys []byte) (Matrix, error) {
+	if field == nil {
+		field = Default
+	}
+	if len(xs) != len(ys) {
+		return Matrix{}, ErrLengthMismatch
+	}
+	if err := checkDistinct(field, append(append([]byte(nil), xs...), ys...)); err != nil {
+		return Matrix{}, err
+	}
+	n := len(xs)
+	ax := make([]byte, n) // a(xs[j])
+	by := make([]byte, n) // b(ys[i])
+	dx := make([]byte, n) // b'(xs[j])
+	dy := make([]byte, n) // a'(ys[i])
+	for i := 0; i < n; i++ {
+		ax[i], by[i], dx[i], dy[i] = 1, 1, 1, 1
+		for k := 0; k < n; k++ {
+			ax[i] = field.Mul(ax[i], xs[i]^ys[k])
+			by[i] = field.Mul(by[i], ys[i]^xs[k])
+			if k != i {
+				dx[i] = field.Mul(dx[i], xs[i]^xs[k])
+				dy[i] = field.Mul(dy[i], ys[i]^ys[k])
+			}
+		}
+	}
+	inv := NewMatrix(field, n, n)
+	for i := 0; i < n; i++ {
+		row := inv.row(i)
+		for j := range row {
+			num := field.Mul(ax[j], by[i])
+			den := field.Mul(field.Mul(xs[j]^ys[i], dy[i]), dx[j])
+			row[j] = field.Div(num, den)
+		}
+	}
+	return inv, nil
+}
+
+// CauchySolve returns the solution a of C*a = b, where C is the square
+// Cauchy matrix with the given points, in O(n**2) field operations.  It
+// returns ErrLengthMismatch if the lengths differ, or the same errors as
+// Cauchy.
+func CauchySolve(field *GF, xs, ys, b []byte) ([]byte, error) {
+	if len(b) != len(xs) {
+		return nil, ErrLengthMismatch
+	}
+	inv, err := CauchyInverse(field, xs, ys)
+	if err != nil {
+		return nil, err
+	}
+	return inv.MulVec(b), nil
+}
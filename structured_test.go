@@ -0,0 +1,122 @@
+package galoisfield
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestVandermonde(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		for trial := 0; trial < 16; trial++ {
+			n := prng.Intn(8)
+			if uint(n) > field.Size() {
+				n = int(field.Size())
+			}
+			xs := randomDistinct(prng, field, n)
+			v, err := Vandermonde(field, xs, n)
+			if err != nil {
+				t.Fatalf("%v: Vandermonde(%v): %v", field, xs, err)
+			}
+			p := NewPolynomial(field, randomCoefficients(prng, int(field.Size()), n)...)
+			values := v.MulVec(expand(n, p.coefficients))
+			for i, x := range xs {
+				if values[i] != p.Evaluate(x) {
+					t.Errorf("%v: V(%v) * (%v) at %d: expected %d, got %d",
+						field, xs, p, x, p.Evaluate(x), values[i])
+				}
+			}
+
+			expected, _ := v.Inverse()
+			if inv, err := VandermondeInverse(field, xs); err != nil || !inv.Equal(expected) {
+				t.Errorf("%v: VandermondeInverse(%v): expected (%v), got (%v), %v",
+					field, xs, expected, inv, err)
+			}
+			b := randomCoefficients(prng, int(field.Size()), n)
+			if a, err := VandermondeSolve(field, xs, b); err != nil || !equalBytes(v.MulVec(a), b) {
+				t.Errorf("%v: VandermondeSolve(%v, %v) = %v, %v", field, xs, b, a, err)
+			}
+		}
+	}
+}
+
+func TestSystematicVandermonde(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		n := 8
+		if uint(n) > field.Size() {
+			n = int(field.Size())
+		}
+		xs := randomDistinct(prng, field, n)
+		k := n / 2
+		g, err := SystematicVandermonde(field, xs, k)
+		if err != nil {
+			t.Fatalf("%v: SystematicVandermonde(%v, %d): %v", field, xs, k, err)
+		}
+		if !g.SubMatrix(columnRange(0, k), nil).Equal(Identity(field, k)) {
+			t.Errorf("%v: top of (%v) is not the identity", field, g)
+		}
+		// Every set of k rows is invertible.
+		for trial := 0; trial < 8; trial++ {
+			rows := prng.Perm(n)[:k]
+			if g.SubMatrix(rows, nil).Rank() != k {
+				t.Errorf("%v: rows %v of (%v) are singular", field, rows, g)
+			}
+		}
+	}
+}
+
+func TestCauchy(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		for trial := 0; trial < 16; trial++ {
+			n := prng.Intn(8)
+			if uint(2*n) > field.Size() {
+				n = int(field.Size()) / 2
+			}
+			points := randomDistinct(prng, field, 2*n)
+			xs, ys := points[:n], points[n:]
+			c, err := Cauchy(field, xs, ys)
+			if err != nil {
+				t.Fatalf("%v: Cauchy(%v, %v): %v", field, xs, ys, err)
+			}
+			expected, err := c.Inverse()
+			if err != nil {
+				t.Errorf("%v: (%v) is singular", field, c)
+				continue
+			}
+			if inv, err := CauchyInverse(field, xs, ys); err != nil || !inv.Equal(expected) {
+				t.Errorf("%v: CauchyInverse(%v, %v): expected (%v), got (%v), %v",
+					field, xs, ys, expected, inv, err)
+			}
+			b := randomCoefficients(prng, int(field.Size()), n)
+			if a, err := CauchySolve(field, xs, ys, b); err != nil || !equalBytes(c.MulVec(a), b) {
+				t.Errorf("%v: CauchySolve(%v, %v, %v) = %v, %v", field, xs, ys, b, a, err)
+			}
+		}
+	}
+}
+
+func TestStructured_errors(t *testing.T) {
+	type testrow struct {
+		fn       func() error
+		expected error
+	}
+	for idx, row := range []testrow{
+		testrow{func() error { _, err := Vandermonde(nil, []byte{1, 2, 1}, 3); return err }, ErrDuplicatePoint},
+		testrow{func() error { _, err := Vandermonde(Poly410_g2, []byte{1, 200}, 2); return err }, ErrPointOutOfRange},
+		testrow{func() error { _, err := VandermondeInverse(nil, []byte{0, 0}); return err }, ErrDuplicatePoint},
+		testrow{func() error { _, err := VandermondeSolve(nil, []byte{0, 1}, []byte{1}); return err }, ErrLengthMismatch},
+		testrow{func() error { _, err := SystematicVandermonde(Poly210_g2, []byte{1, 4}, 1); return err }, ErrPointOutOfRange},
+		testrow{func() error { _, err := Cauchy(nil, []byte{1, 2}, []byte{3, 1}); return err }, ErrDuplicatePoint},
+		testrow{func() error { _, err := CauchyInverse(nil, []byte{1, 2}, []byte{3}); return err }, ErrLengthMismatch},
+		testrow{func() error { _, err := CauchySolve(nil, []byte{1}, []byte{3}, nil); return err }, ErrLengthMismatch},
+	} {
+		if err := row.fn(); err != row.expected {
+			t.Errorf("[%d] expected %v, got %v", idx, row.expected, err)
+		}
+	}
+	if e := panicValue(func() { SystematicVandermonde(nil, []byte{1}, 2) }); e != ErrDimensionMismatch {
+		t.Errorf("expected panic(%v), got %v", ErrDimensionMismatch, e)
+	}
+}
@@ -0,0 +1,311 @@
+package galoisfield
+
+// subproductTree holds the products of the linear factors (x - xs[i]) for a
+// list of points, arranged as a binary tree.  Level 0 holds the linear
+// factors themselves, and node i of level l holds the product over
+// xs[i<<l : (i+1)<<l].  When a level has an odd number of nodes, the last one
+// is carried up to the next level unchanged.  The root is ∏_i (x - xs[i]).
+//
+// The tree makes multipoint evaluation and interpolation divide-and-conquer
+// problems: each point only needs the remainder of the polynomial modulo the
+// leaf above it, and those remainders can be computed level by level, with
+// the degree halving at each step.
+type subproductTree struct {
+	field  *GF
+	xs     []byte
+	levels [][][]byte
+}
+
+// subproductLeafSize is the node size below which the tree algorithms switch
+// to evaluating or combining the points directly.
+const subproductLeafSize = 8
+
+func newSubproductTree(field *GF, xs []byte) *subproductTree {
+	leaves := make([][]byte, len(xs))
+	for i, x := range xs {
+		leaves[i] = []byte{x, 1}
+	}
+	levels := [][][]byte{leaves}
+	for len(leaves) > 1 {
+		next := make([][]byte, (len(leaves)+1)/2)
+		for i := range next {
+			if 2*i+1 < len(leaves) {
+				next[i] = polyMul(field, leaves[2*i], leaves[2*i+1])
+			} else {
+				next[i] = leaves[2*i]
+			}
+		}
+		levels = append(levels, next)
+		leaves = next
+	}
+	return &subproductTree{field, xs, levels}
+}
+
+// root returns ∏_i (x - xs[i]).
+func (t *subproductTree) root() []byte {
+	if len(t.xs) == 0 {
+		return []byte{1}
+	}
+	return t.levels[len(t.levels)-1][0]
+}
+
+// span returns the range of points under node i of the given level.
+func (t *subproductTree) span(level, i int) (lo, hi int) {
+	lo = i << uint(level)
+	hi = (i + 1) << uint(level)
+	if hi > len(t.xs) {
+		hi = len(t.xs)
+	}
+	return lo, hi
+}
+
+// evaluate returns a(xs[i]) for every point.
+func (t *subproductTree) evaluate(a []byte) []byte {
+	out := make([]byte, len(t.xs))
+	if len(t.xs) == 0 {
+		return out
+	}
+	top := len(t.levels) - 1
+	t.evaluateNode(top, 0, polyRem(t.field, a, t.root()), out)
+	return out
+}
+
+func (t *subproductTree) evaluateNode(level, i int, r []byte, out []byte) {
+	lo, hi := t.span(level, i)
+	if level == 0 || hi-lo <= subproductLeafSize {
+		for j := lo; j < hi; j++ {
+			out[j] = horner(t.field, r, t.xs[j])
+		}
+		return
+	}
+	children := t.levels[level-1]
+	if 2*i+1 >= len(children) {
+		t.evaluateNode(level-1, 2*i, r, out)
+		return
+	}
+	t.evaluateNode(level-1, 2*i, polyRem(t.field, r, children[2*i]), out)
+	t.evaluateNode(level-1, 2*i+1, polyRem(t.field, r, children[2*i+1]), out)
+}
+
+// combine returns ∑_i c[i] * root(x) / (x - xs[i]).  With c[i] = y[i] / w'(x[i])
+// where w is the root, this is the interpolating polynomial.
+func (t *subproductTree) combine(c []byte) []byte {
+	if len(t.xs) == 0 {
+		return nil
+	}
+	return t.combineNode(len(t.levels)-1, 0, c)
+}
+
+func (t *subproductTree) combineNode(level, i int, c []byte) []byte {
+	field := t.field
+	if level == 0 {
+		return []byte{c[i]}
+	}
+	children := t.levels[level-1]
+	if 2*i+1 >= len(children) {
+		return t.combineNode(level-1, 2*i, c)
+	}
+	left := polyMul(field, t.combineNode(level-1, 2*i, c), children[2*i+1])
+	right := polyMul(field, t.combineNode(level-1, 2*i+1, c), children[2*i])
+	n := len(left)
+	if len(right) > n {
+		n = len(right)
+	}
+	sum := expand(n, left)
+	for j, rj := range right {
+		sum[j] ^= rj
+	}
+	return reduce(sum)
+}
+
+// EvaluateMany returns the values of this polynomial at each of the given
+// points, in the same order.  It uses a subproduct tree (see FromRoots),
+// which is faster than calling Evaluate at each point when there are many
+// points and the polynomial has high degree (see
+// BenchmarkPolynomial_EvaluateMany_*).
+func (a Polynomial) EvaluateMany(xs []byte) []byte {
+	if len(xs) <= subproductLeafSize {
+		out := make([]byte, len(xs))
+		for i, x := range xs {
+			out[i] = a.Evaluate(x)
+		}
+		return out
+	}
+	return newSubproductTree(a.field, xs).evaluate(a.coefficients)
+}
+
+// FromRoots returns the monic polynomial ∏_i (x - roots[i]).  Repeated roots
+// are allowed, and give factors of higher multiplicity.  If field is nil,
+// Default is used.
+//
+// The product is computed as a balanced binary tree of multiplications, which
+// keeps the operands of each multiplication of similar size.
+func FromRoots(field *GF, roots []byte) Polynomial {
+	if field == nil {
+		field = Default
+	}
+	return wrapPolynomial(field, newSubproductTree(field, roots).root())
+}
+
+// interpolateTree is equivalent to Interpolate, but uses a subproduct tree
+// for both computing the weights w'(xs[i]) and combining the terms.  The
+// points must be distinct.
+func interpolateTree(field *GF, xs, ys []byte) []byte {
+	t := newSubproductTree(field, xs)
+	w := wrapPolynomial(field, t.root())
+	denom := t.evaluate(w.Derivative().coefficients)
+	c := make([]byte, len(xs))
+	for i := range c {
+		c[i] = field.Div(ys[i], denom[i])
+	}
+	return t.combine(c)
+}
+
+// horner returns a(x), using Horner's rule.  Since x is fixed, its logarithm
+// is looked up once, leaving a single table lookup per multiplication.  The
+// even and odd terms are accumulated separately, as polynomials in x**2, so
+// that the two dependency chains of lookups can overlap.
+func horner(field *GF, a []byte, x byte) byte {
+	if x == 0 || len(a) == 0 {
+		if len(a) == 0 {
+			return 0
+		}
+		return a[0]
+	}
+	logx := uint(field.log[x])
+	logx2 := (2 * logx) % field.m
+	var even, odd byte
+	i := len(a) - 1
+	if i%2 == 0 {
+		even = a[i]
+		i--
+	}
+	for ; i > 0; i -= 2 {
+		if odd != 0 {
+			odd = field.exp[uint(field.log[odd])+logx2]
+		}
+		if even != 0 {
+			even = field.exp[uint(field.log[even])+logx2]
+		}
+		odd ^= a[i]
+		even ^= a[i-1]
+	}
+	if odd != 0 {
+		odd = field.exp[uint(field.log[odd])+logx]
+	}
+	return even ^ odd
+}
+
+// polyRemNewtonMin is the length of the quotient, and of the modulus, from
+// which polyRem replaces long division by multiplication with a Newton
+// inverse (see BenchmarkPolyRem).  The crossover is high because the Newton
+// method costs several multiplications, each only subquadratic through
+// Karatsuba's method and Toom-3.
+const polyRemNewtonMin = 512
+
+// polyRem returns a mod m, leaving a unmodified.  If a already has lower
+// degree than m, it is returned as is.
+//
+// Long division takes O(len(a-m) * len(m)) field operations, which would
+// make the subproduct tree algorithms quadratic, so large remainders are
+// instead computed from a Newton inverse of the reversed modulus with the
+// fast multiplication of polyMul.
+func polyRem(field *GF, a, m []byte) []byte {
+	if len(a) < len(m) {
+		return a
+	}
+	if len(a)-len(m)+1 < polyRemNewtonMin || len(m) < polyRemNewtonMin {
+		rem := append([]byte(nil), a...)
+		polyDivMod(field, nil, rem, m)
+		return reduce(rem[:len(m)-1])
+	}
+	return polyRemNewton(field, a, m)
+}
+
+// polyRemNewton returns a mod m, where len(a) >= len(m) >= 2.
+//
+// With n = deg(m), k = len(a) - n and rev(p) the coefficients of p in
+// reverse order, the quotient q satisfies rev(q) = rev(a) / rev(m) mod x**k,
+// and rev(m) is invertible as a power series since its constant term is the
+// leading coefficient of m.  The remainder is then a - q*m.  A quotient
+// longer than n is taken n coefficients at a time, from the top of a down,
+// so that the inverse is only needed to precision n and each step costs a
+// couple of balanced multiplications.
+func polyRemNewton(field *GF, a, m []byte) []byte {
+	n := len(m) - 1
+	prec := len(a) - n
+	if prec > n {
+		prec = n
+	}
+	inv := reversedInverse(field, m, prec)
+	rem := append([]byte(nil), a...)
+	for len(rem) > n {
+		k := len(rem) - n
+		if k > prec {
+			k = prec
+		}
+		top := rem[len(rem)-n-k:]
+		remStep(field, top, m, inv[:k])
+		rem = rem[:len(rem)-k]
+	}
+	return reduce(rem)
+}
+
+// remStep reduces s, whose length is deg(m) + len(inv), modulo m in place,
+// leaving the remainder in its low deg(m) coefficients.  inv must be
+// 1/rev(m) mod x**len(inv).
+func remStep(field *GF, s, m, inv []byte) {
+	n, k := len(m)-1, len(inv)
+	// The top k coefficients of s, reversed, determine rev(q).
+	rs := make([]byte, k)
+	for i := range rs {
+		rs[i] = s[len(s)-1-i]
+	}
+	rq := polyMul(field, rs, inv)
+	if len(rq) > k {
+		rq = rq[:k]
+	}
+	q := make([]byte, k)
+	for i, c := range rq {
+		q[k-1-i] = c
+	}
+	// Only the low n coefficients of q*m are needed, and the leading term
+	// of m contributes nothing to them.
+	qm := polyMul(field, q, m[:n])
+	if len(qm) > n {
+		qm = qm[:n]
+	}
+	for i, c := range qm {
+		s[i] ^= c
+	}
+}
+
+// reversedInverse returns 1/rev(m) mod x**k, by Newton iteration.  In
+// characteristic 2, the step g -> g*(2 - f*g) that doubles the precision of an
+// inverse g of f is simply g -> f*g**2, and squaring is cheap.
+func reversedInverse(field *GF, m []byte, k int) []byte {
+	f := make([]byte, len(m))
+	for i, c := range m {
+		f[len(m)-1-i] = c
+	}
+	g := []byte{field.Inv(f[0])}
+	for prec := 1; prec < k; {
+		prec *= 2
+		if prec > k {
+			prec = k
+		}
+		sq := make([]byte, 2*len(g)-1)
+		for i, c := range g {
+			sq[2*i] = field.Mul(c, c)
+		}
+		ft := f
+		if len(ft) > prec {
+			ft = ft[:prec]
+		}
+		g = polyMul(field, ft, sq)
+		if len(g) > prec {
+			g = g[:prec]
+		}
+	}
+	return expand(k, g)
+}
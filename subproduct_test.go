@@ -0,0 +1,257 @@
+package galoisfield
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func TestFromRoots(t *testing.T) {
+	type testrow struct {
+		field    *GF
+		roots    []byte
+		expected Polynomial
+	}
+	for idx, row := range []testrow{
+		testrow{nil, nil, NewPolynomial(nil, 1)},
+		testrow{nil, []byte{0}, NewPolynomial(nil, 0, 1)},
+		testrow{nil, []byte{7}, NewPolynomial(nil, 7, 1)},
+		testrow{nil, []byte{1, 1}, NewPolynomial(nil, 1, 0, 1)},
+		testrow{nil, []byte{2, 3}, NewPolynomial(nil, 6, 1, 1)},
+		testrow{nil, []byte{0, 2, 3}, NewPolynomial(nil, 0, 6, 1, 1)},
+		testrow{Poly210_g2, []byte{0, 1, 2, 3}, NewPolynomial(Poly210_g2, 0, 1, 0, 0, 1)},
+	} {
+		actual := FromRoots(row.field, row.roots)
+		if !actual.Equal(row.expected) {
+			t.Errorf("[%2d] expected FromRoots(%v) to be (%v), got (%v)",
+				idx, row.roots, row.expected, actual)
+		}
+	}
+}
+
+func TestFromRoots_random(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		n := int(field.Size())
+		for trial := 0; trial < 16; trial++ {
+			roots := make([]byte, prng.Intn(2*n))
+			expected := NewPolynomial(field, 1)
+			for i := range roots {
+				roots[i] = byte(prng.Intn(n))
+				expected = expected.Mul(NewPolynomial(field, roots[i], 1))
+			}
+			actual := FromRoots(field, roots)
+			if !actual.Equal(expected) {
+				t.Errorf("expected FromRoots(%v) to be (%v), got (%v)",
+					roots, expected, actual)
+			}
+		}
+	}
+}
+
+func TestPolynomial_EvaluateMany(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		n := int(field.Size())
+		for trial := 0; trial < 16; trial++ {
+			p := make([]byte, prng.Intn(3*n))
+			for i := range p {
+				p[i] = byte(prng.Intn(n))
+			}
+			poly := NewPolynomial(field, p...)
+			xs := make([]byte, prng.Intn(2*n))
+			for i := range xs {
+				xs[i] = byte(prng.Intn(n))
+			}
+			actual := poly.EvaluateMany(xs)
+			if len(actual) != len(xs) {
+				t.Fatalf("expected %d values, got %d", len(xs), len(actual))
+			}
+			for i, x := range xs {
+				if expected := evaluateByPowers(poly, x); actual[i] != expected {
+					t.Errorf("(%v).EvaluateMany: at x=%d expected %d, got %d",
+						poly, x, expected, actual[i])
+				}
+			}
+		}
+	}
+}
+
+func TestInterpolate_tree(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range fields {
+		n := int(field.Size())
+		for trial := 0; trial < 8; trial++ {
+			xs := randomDistinct(prng, field, 1+prng.Intn(n))
+			ys := make([]byte, len(xs))
+			for i := range ys {
+				ys[i] = byte(prng.Intn(n))
+			}
+			expected := NewPolynomial(field, interpolateLagrange(field, xs, ys)...)
+			actual := NewPolynomial(field, interpolateTree(field, xs, ys)...)
+			if !actual.Equal(expected) {
+				t.Errorf("interpolateTree(%v, %v): expected (%v), got (%v)",
+					xs, ys, expected, actual)
+			}
+		}
+	}
+}
+
+func TestPolyRem(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	for _, field := range []*GF{Poly210_g2, Poly410_g2, Default} {
+		q := int(field.Size())
+		for _, sizes := range [][2]int{
+			{10, 20}, {100, 64}, {127, 64}, {128, 64}, {129, 64}, {200, 65},
+			{500, 100}, {1000, 129}, {1025, 257}, {3000, 300}, {1200, 600}, {5000, 700},
+		} {
+			a := randomCoefficients(prng, q, sizes[0])
+			m := randomCoefficients(prng, q, sizes[1])
+			expected := append([]byte(nil), a...)
+			if len(a) >= len(m) {
+				polyDivMod(field, nil, expected, m)
+				expected = expected[:len(m)-1]
+			}
+			actual := polyRem(field, a, m)
+			if !equalBytes(actual, reduce(expected)) {
+				t.Errorf("%v: polyRem of lengths %d and %d disagrees with long division",
+					field, len(a), len(m))
+			}
+			if len(a) >= len(m) && len(m) >= 2 {
+				if actual := polyRemNewton(field, a, m); !equalBytes(actual, reduce(expected)) {
+					t.Errorf("%v: polyRemNewton of lengths %d and %d disagrees with long division",
+						field, len(a), len(m))
+				}
+			}
+		}
+	}
+}
+
+// evaluateByPowers is the original implementation of Evaluate, which
+// computes each power of x with a separate multiplication.
+func evaluateByPowers(a Polynomial, x byte) byte {
+	var sum byte = 0
+	var pow byte = 1
+	for _, k := range a.coefficients {
+		sum = a.field.Add(sum, a.field.Mul(k, pow))
+		pow = a.field.Mul(pow, x)
+	}
+	return sum
+}
+
+func benchmarkPolynomial(degree int) Polynomial {
+	prng := rand.New(rand.NewSource(42))
+	p := make([]byte, degree+1)
+	for i := range p {
+		p[i] = byte(1 + prng.Intn(255))
+	}
+	return NewPolynomial(Default, p...)
+}
+
+func allPoints() []byte {
+	xs := make([]byte, 256)
+	for i := range xs {
+		xs[i] = byte(i)
+	}
+	return xs
+}
+
+func BenchmarkPolynomial_Evaluate_256(b *testing.B) {
+	p := benchmarkPolynomial(255)
+	for i := 0; i < b.N; i++ {
+		_ = p.Evaluate(byte(i))
+	}
+}
+
+func BenchmarkPolynomial_Evaluate_byPowers_256(b *testing.B) {
+	p := benchmarkPolynomial(255)
+	for i := 0; i < b.N; i++ {
+		_ = evaluateByPowers(p, byte(i))
+	}
+}
+
+func BenchmarkPolynomial_EvaluateMany_256(b *testing.B) {
+	p := benchmarkPolynomial(1023)
+	xs := allPoints()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = p.EvaluateMany(xs)
+	}
+}
+
+func BenchmarkPolynomial_EvaluateMany_horner_256(b *testing.B) {
+	p := benchmarkPolynomial(1023)
+	xs := allPoints()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, x := range xs {
+			_ = p.Evaluate(x)
+		}
+	}
+}
+
+func BenchmarkPolynomial_EvaluateMany_byPowers_256(b *testing.B) {
+	p := benchmarkPolynomial(1023)
+	xs := allPoints()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, x := range xs {
+			_ = evaluateByPowers(p, x)
+		}
+	}
+}
+
+func BenchmarkFromRoots_256(b *testing.B) {
+	xs := allPoints()
+	for i := 0; i < b.N; i++ {
+		_ = FromRoots(Default, xs)
+	}
+}
+
+func BenchmarkFromRoots_sequential_256(b *testing.B) {
+	xs := allPoints()
+	for i := 0; i < b.N; i++ {
+		p := NewPolynomial(Default, 1)
+		for _, x := range xs {
+			p = p.Mul(NewPolynomial(Default, x, 1))
+		}
+	}
+}
+
+func BenchmarkInterpolate_tree_256(b *testing.B) {
+	xs := allPoints()
+	ys := benchmarkPolynomial(255).Coefficients()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = interpolateTree(Default, xs, ys)
+	}
+}
+
+func BenchmarkInterpolate_lagrange_256(b *testing.B) {
+	xs := allPoints()
+	ys := benchmarkPolynomial(255).Coefficients()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = interpolateLagrange(Default, xs, ys)
+	}
+}
+
+func BenchmarkPolyRem(b *testing.B) {
+	prng := rand.New(rand.NewSource(42))
+	for _, n := range []int{32, 64, 128, 256, 512, 1024} {
+		a := randomCoefficients(prng, 256, 2*n)
+		m := randomCoefficients(prng, 256, n+1)
+		b.Run(fmt.Sprintf("newton_%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_ = polyRemNewton(Default, a, m)
+			}
+		})
+		b.Run(fmt.Sprintf("longDivision_%d", n), func(b *testing.B) {
+			rem := make([]byte, len(a))
+			for i := 0; i < b.N; i++ {
+				copy(rem, a)
+				polyDivMod(Default, nil, rem, m)
+			}
+		})
+	}
+}